@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleBranch opens the branch panel; a description opens its new branch
+// form, named with the configured prefix and naming style
+func HandleBranch(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/branch "+args))
+	resetInput(m)
+
+	root, err := git.Root()
+	if err != nil {
+		return fail(err, m)
+	}
+	branches, err := git.Branches(root)
+	if err != nil {
+		return fail(err, m)
+	}
+	m.Panel = panels.NewBranchPanel(root, branches, args, m.Overrides.DryRun)
+	return nil
+}
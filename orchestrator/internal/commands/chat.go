@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bounds on what a chat message sends along; older turns are dropped first
+const (
+	maxChatTranscript  = 24000
+	maxChatContextFile = 20000
+)
+
+const chatPrompt = `You are a helpful assistant inside a terminal git workflow tool. Answer the user's latest message about their repository concisely, in plain text suitable for a terminal. The input contains the repository's GEMINI.md context, attached files and the conversation so far.`
+
+// HandleChat sends input that is not a command to Gemini, together with the
+// repository's GEMINI.md, attached files and the conversation so far. The
+// answer streams into history until it ends or Esc cancels it.
+func HandleChat(input string, m *models.Model) tea.Cmd {
+	// Add message to history
+	m.Messages = append(m.Messages, input)
+	resetInput(m)
+
+	if m.ChatStream != nil {
+		m.AddResult("❌ Gemini is still answering the previous message")
+		return nil
+	}
+	backend, model, err := m.LLM()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	context, err := m.RepoContext()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	m.Chat = append(m.Chat, models.ChatTurn{Role: "user", Text: input})
+	stream := backend.Stream(llm.Request{Model: model, Prompt: chatPrompt, Input: chatInput(context.Text(), m.Chat, m.ContextFiles)})
+	m.ChatStream = stream
+	m.ChatReply = ""
+	return stream.Next()
+}
+
+// ContinueChat adds a streamed chunk to the answer and waits for the next
+func ContinueChat(msg llm.ChunkMsg, m *models.Model) tea.Cmd {
+	m.ChatReply += msg.Text
+	return msg.Stream.Next()
+}
+
+// CancelChat stops the answer being streamed; what arrived so far is kept
+func CancelChat(m *models.Model) {
+	if m.ChatStream != nil {
+		m.ChatStream.Cancel()
+	}
+}
+
+// FinishChat adds the complete answer to history and the conversation. A
+// cancelled answer is kept as far as it got; a failed message is forgotten
+// so it is not sent again as context. The end of a stream that is no
+// longer current, as after /clear, is ignored.
+func FinishChat(msg llm.DoneMsg, m *models.Model) tea.Cmd {
+	if msg.Stream != m.ChatStream {
+		return nil
+	}
+	reply := strings.TrimSpace(m.ChatReply)
+	m.ChatStream = nil
+	m.ChatReply = ""
+
+	if reply != "" {
+		m.AddResponse(reply)
+	}
+	switch {
+	case errors.Is(msg.Err, llm.ErrCancelled):
+		if reply != "" {
+			m.Chat = append(m.Chat, models.ChatTurn{Role: "model", Text: reply})
+		} else if len(m.Chat) > 0 {
+			m.Chat = m.Chat[:len(m.Chat)-1]
+		}
+		m.AddResult("Cancelled")
+	case msg.Err != nil:
+		if len(m.Chat) > 0 {
+			m.Chat = m.Chat[:len(m.Chat)-1]
+		}
+		m.AddResult(fmt.Sprintf("❌ %v", msg.Err))
+	default:
+		m.Chat = append(m.Chat, models.ChatTurn{Role: "model", Text: reply})
+	}
+	return AdvanceScript(m, msg.Err)
+}
+
+// chatInput builds the standard input of a chat prompt
+func chatInput(context string, turns []models.ChatTurn, files []string) string {
+	var b strings.Builder
+
+	if context != "" {
+		b.WriteString("Repository context (GEMINI.md):\n" + context + "\n\n")
+	}
+
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		text := string(content)
+		if len(text) > maxChatContextFile {
+			text = utils.TruncateBytes(text, maxChatContextFile) + "\n[truncated]"
+		}
+		fmt.Fprintf(&b, "Attached file %s:\n%s\n\n", path, text)
+	}
+
+	b.WriteString("Conversation:\n")
+	b.WriteString(chatTranscript(turns, maxChatTranscript))
+	return b.String()
+}
+
+// chatTranscript renders the most recent turns that fit in limit bytes,
+// always keeping the latest one
+func chatTranscript(turns []models.ChatTurn, limit int) string {
+	start := len(turns) - 1
+	size := len(turns[start].Text)
+	for start > 0 && size+len(turns[start-1].Text) <= limit {
+		start--
+		size += len(turns[start].Text)
+	}
+
+	var b strings.Builder
+	for _, turn := range turns[start:] {
+		speaker := "User"
+		if turn.Role == "model" {
+			speaker = "Assistant"
+		}
+		fmt.Fprintf(&b, "%s: %s\n\n", speaker, turn.Text)
+	}
+	return b.String()
+}
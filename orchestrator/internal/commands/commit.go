@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recentCommitCount is how much history the commit prompt shows, as in
+// auto_commit.zsh
+const recentCommitCount = 5
+
+// HandleCommit writes a commit message for the staged changes with Gemini
+// in the commit panel. Text after /commit is extra context for Gemini.
+// --zsh, or any auto-commit flag such as --pr, runs the auto-commit
+// script instead.
+func HandleCommit(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/commit "+args))
+	resetInput(m)
+
+	if script, ok := scriptArgs(args); ok {
+		if err := requireExecutable("auto-commit"); err != nil {
+			return fail(err, m)
+		}
+		return executeZshCommand(strings.TrimSpace("auto-commit "+script), m)
+	}
+
+	status, err := git.LoadStatus()
+	if err != nil {
+		return fail(err, m)
+	}
+	if len(status.Staged()) > 0 {
+		return confirmCommitBranch(status, args, false, m)
+	}
+	if len(status.Unstaged())+len(status.Untracked()) == 0 {
+		return fail(fmt.Errorf("nothing to commit, the working tree is clean"), m)
+	}
+
+	return askCommit("Nothing is staged. Stage all changes?", m, func(m *models.Model) tea.Cmd {
+		if m.Overrides.DryRun {
+			// Show the message for what staging would commit, without staging
+			if status.Head == "" {
+				return fail(fmt.Errorf("dry run cannot preview staging before the first commit"), m)
+			}
+			m.AddResult("🔍 Dry run: would stage all changes")
+			return confirmCommitBranch(status, args, true, m)
+		}
+		if err := git.Stage(status.Root, "."); err != nil {
+			return fail(err, m)
+		}
+		m.AddResult("Staged all changes")
+		return confirmCommitBranch(status, args, false, m)
+	})
+}
+
+// confirmCommitBranch asks before committing straight to main or master,
+// where auto-commit would offer to create a branch
+func confirmCommitBranch(status *git.Status, context string, unstaged bool, m *models.Model) tea.Cmd {
+	if status.Branch != "main" && status.Branch != "master" {
+		return openCommitPanel(status.Root, context, unstaged, m)
+	}
+	prompt := fmt.Sprintf("Commit directly to '%s'? (/branch starts a new branch)", status.Branch)
+	return askCommit(prompt, m, func(m *models.Model) tea.Cmd {
+		return openCommitPanel(status.Root, context, unstaged, m)
+	})
+}
+
+// askCommit asks a question on the way to the commit panel; no cancels
+func askCommit(prompt string, m *models.Model, yes func(m *models.Model) tea.Cmd) tea.Cmd {
+	m.Confirm = &models.Confirm{
+		Prompt: prompt,
+		OnAnswer: func(answer bool, m *models.Model) tea.Cmd {
+			if answer {
+				return yes(m)
+			}
+			m.AddResult("Commit cancelled")
+			return AdvanceScript(m, nil)
+		},
+	}
+	return nil
+}
+
+// openCommitPanel gathers what build_commit_prompt uses and starts
+// generating the message. With unstaged, in a dry run that skipped
+// staging, it uses every change against HEAD as if git add -A had run;
+// untracked files are listed but their content is not in the diff.
+func openCommitPanel(root, context string, unstaged bool, m *models.Model) tea.Cmd {
+	status, err := git.LoadStatus()
+	if err != nil {
+		return fail(err, m)
+	}
+	files := status.Staged()
+	diffOptions := git.DiffOptions{Staged: true}
+	if unstaged {
+		files = status.Files
+		diffOptions = git.DiffOptions{Revs: []string{"HEAD"}}
+	}
+	if len(files) == 0 {
+		return fail(fmt.Errorf("nothing is staged"), m)
+	}
+
+	input := panels.CommitInput{
+		Root:       root,
+		Branch:     status.Branch,
+		Extra:      context,
+		Repository: git.RepoSlug(root),
+		Issue:      git.BranchIssue(root, status.Branch),
+		DryRun:     m.Overrides.DryRun,
+	}
+	for _, f := range files {
+		input.Files = append(input.Files, f.Path)
+	}
+	if input.Diff, err = git.DiffText(root, diffOptions); err != nil {
+		return fail(err, m)
+	}
+	if input.Recent, err = git.RecentCommits(root, recentCommitCount); err != nil {
+		return fail(err, m)
+	}
+	repoContext, err := m.RepoContext(input.Files...)
+	if err != nil {
+		return fail(err, m)
+	}
+	input.Context = repoContext.Text()
+
+	panel := panels.NewCommitPanel(input)
+	m.Panel = panel
+	return panel.Generate(m)
+}
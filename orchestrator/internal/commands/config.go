@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/control"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const configUsage = "usage: /config [set [repo|user|default] KEY=value | unset [repo|user|default] KEY]"
+
+// HandleConfig opens the configuration editor, or changes a single key so
+// batch scripts can adjust settings without the panel
+func HandleConfig(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/config "+args))
+	resetInput(m)
+
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		m.Panel = panels.NewConfigPanel(m)
+		return nil
+	}
+
+	action, fields := fields[0], fields[1:]
+	tier := config.SourceRepo
+	if len(fields) > 1 {
+		tier = config.Source(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return fail(fmt.Errorf(configUsage), m)
+	}
+	path := m.Config.Paths.PathFor(tier)
+	if path == "" {
+		return fail(fmt.Errorf("unknown tier %q (use repo, user or default)", tier), m)
+	}
+
+	switch action {
+	case "set":
+		key, value, found := strings.Cut(fields[0], "=")
+		if !found {
+			return fail(fmt.Errorf(configUsage), m)
+		}
+		if err := config.Set(path, key, value); err != nil {
+			return fail(err, m)
+		}
+		m.AddResult(fmt.Sprintf("Set %s=%s in %s (%s)", key, value, tier, path))
+	case "unset":
+		if err := config.Unset(path, fields[0]); err != nil {
+			return fail(err, m)
+		}
+		m.AddResult(fmt.Sprintf("Removed %s from %s (%s)", fields[0], tier, path))
+	default:
+		return fail(fmt.Errorf(configUsage), m)
+	}
+
+	c, err := config.Load(m.Config.Paths)
+	if err != nil {
+		return fail(err, m)
+	}
+	m.Config = c
+	return nil
+}
+
+// ReloadConfig re-applies the configuration after a tier file changed on disk.
+// An edit that introduces invalid values is rejected as a whole, so the
+// session never runs with a partially applied file.
+func ReloadConfig(m *models.Model) {
+	c, err := config.Load(m.Config.Paths)
+	if err != nil {
+		m.Messages = append(m.Messages, fmt.Sprintf("⚠️  Configuration changed but could not be read: %v", err))
+		return
+	}
+
+	if problems := c.NewProblems(m.Config); len(problems) > 0 {
+		m.Messages = append(m.Messages, "❌ Configuration change rejected, keeping the previous values")
+		for _, problem := range problems {
+			m.AddResult(problem)
+		}
+		return
+	}
+
+	// Files written by /config were already applied
+	changes := c.Diff(m.Config)
+	m.Config = c
+	if len(changes) == 0 {
+		return
+	}
+
+	m.Messages = append(m.Messages, "⚙️  Configuration reloaded")
+	data := map[string]string{}
+	for _, change := range changes {
+		m.AddResult(change.String())
+		data[change.Key] = change.New.Value
+	}
+	m.Publish(control.EventConfigReloaded, data)
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleContext shows the GEMINI.md context sent to Gemini from the working
+// directory; paths add the context of the directories they are in, as the
+// changed files do for generated commit messages
+func HandleContext(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/context "+args))
+	resetInput(m)
+
+	var paths []string
+	if root, err := git.Root(); err == nil {
+		for _, arg := range strings.Fields(args) {
+			paths = append(paths, rootRelative(root, arg))
+		}
+	}
+
+	context, err := m.RepoContext(paths...)
+	if err != nil {
+		return fail(err, m)
+	}
+	m.Panel = panels.NewContextPanel(context)
+	return nil
+}
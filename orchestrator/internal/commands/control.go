@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gemini-orchestrator/internal/control"
+	"gemini-orchestrator/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleControlRequest applies a request received on the control socket
+func HandleControlRequest(msg control.RequestMsg, m *models.Model) tea.Cmd {
+	req := msg.Request
+
+	switch req.Type {
+	case control.RequestCommand:
+		if req.Command == "" {
+			msg.Reply(nil, fmt.Errorf("command is required"))
+			return nil
+		}
+		if m.Script != nil {
+			msg.Reply(nil, fmt.Errorf("a batch script is running"))
+			return nil
+		}
+		m.Publish(control.EventCommandReceived, map[string]string{"command": req.Command})
+		msg.Reply(nil, nil)
+
+		// Commands arrive exactly as if typed, including zsh mode with a leading "!"
+		if strings.HasPrefix(req.Command, "!") {
+			return HandleZshCommand(strings.TrimSpace(req.Command[1:]), m)
+		}
+		return HandleCommand(req.Command, m)
+
+	case control.RequestInsert:
+		value := []rune(m.TextInput.Value())
+		cursor := m.TextInput.Position()
+		inserted := []rune(req.Text)
+
+		m.TextInput.SetValue(string(value[:cursor]) + req.Text + string(value[cursor:]))
+		m.TextInput.SetCursor(cursor + len(inserted))
+		m.UpdateSuggestions()
+		msg.Reply(nil, nil)
+		return nil
+
+	case control.RequestOpen:
+		path, err := addContextFile(req.Path, m)
+		if err != nil {
+			msg.Reply(nil, err)
+			return nil
+		}
+		m.Messages = append(m.Messages, "@"+path)
+		m.AddResult("Added to file context")
+		msg.Reply(map[string]string{"path": path}, nil)
+		return nil
+	}
+
+	msg.Reply(nil, fmt.Errorf("unsupported request type %q", req.Type))
+	return nil
+}
+
+// addContextFile validates path and records it relative to the working directory
+func addContextFile(path string, m *models.Model) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(cwd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+
+	if !slices.Contains(m.ContextFiles, path) {
+		m.ContextFiles = append(m.ContextFiles, path)
+	}
+	return path, nil
+}
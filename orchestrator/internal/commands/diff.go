@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleDiff opens the diff viewer. Arguments that name an existing file
+// or directory limit the diff to it, anything else is a revision or range;
+// "--" forces the rest to be paths. Other options are refused rather than
+// passed on to git.
+func HandleDiff(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/diff "+args))
+	resetInput(m)
+
+	root, err := git.Root()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	var options git.DiffOptions
+	var rest []string
+	for _, arg := range strings.Fields(args) {
+		if arg == "--staged" || arg == "--cached" {
+			options.Staged = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	options.Revs, options.Paths, err = revisionsAndPaths(root, rest)
+	if err != nil {
+		return fail(err, m)
+	}
+
+	// The diff runs in the panel so large ones do not block the UI
+	panel := panels.NewDiffPanel(root, options, diffTitle(options))
+	m.Panel = panel
+	return panel.Load()
+}
+
+// revisionsAndPaths sorts arguments into revisions and paths relative to
+// root: names of existing files or directories are paths, anything else a
+// revision or range, and everything after "--" a path. A revision starting
+// with "-" would reach git as an option, such as --output=<file>, so it is
+// an error.
+func revisionsAndPaths(root string, args []string) (revs, paths []string, err error) {
+	onlyPaths := false
+	for _, arg := range args {
+		switch {
+		case onlyPaths:
+			paths = append(paths, rootRelative(root, arg))
+		case arg == "--":
+			onlyPaths = true
+		default:
+			if _, err := os.Stat(arg); err == nil {
+				paths = append(paths, rootRelative(root, arg))
+			} else if strings.HasPrefix(arg, "-") {
+				return nil, nil, fmt.Errorf("%s is not a revision or an existing path, and options are not supported", arg)
+			} else {
+				revs = append(revs, arg)
+			}
+		}
+	}
+	return revs, paths, nil
+}
+
+// diffTitle describes what a diff compares, like "HEAD~2 → working tree"
+func diffTitle(options git.DiffOptions) string {
+	var title string
+	switch {
+	case len(options.Revs) == 1 && strings.Contains(options.Revs[0], ".."):
+		title = options.Revs[0]
+	case len(options.Revs) == 2:
+		title = options.Revs[0] + " → " + options.Revs[1]
+	case len(options.Revs) == 1 && options.Staged:
+		title = options.Revs[0] + " → index"
+	case len(options.Revs) == 1:
+		title = options.Revs[0] + " → working tree"
+	case options.Staged:
+		title = "HEAD → index"
+	default:
+		title = "index → working tree"
+	}
+	if len(options.Paths) > 0 {
+		title += "  (" + strings.Join(options.Paths, ", ") + ")"
+	}
+	return title
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"gemini-orchestrator/internal/doctor"
+	"gemini-orchestrator/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleDoctor checks the environment in the background and reports every result
+func HandleDoctor(m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, "/doctor")
+	resetInput(m)
+	m.AddResult("Checking environment...")
+
+	return doctorCmd(false)
+}
+
+// StartupDoctor checks the environment when the orchestrator starts
+// Only problems are reported, so a healthy setup starts silently
+func StartupDoctor() tea.Cmd {
+	return doctorCmd(true)
+}
+
+// ReportDoctor adds a doctor report to history
+func ReportDoctor(msg models.DoctorMsg, m *models.Model) {
+	passed, warnings, failures := doctor.Summary(msg.Results)
+
+	if msg.Startup {
+		if warnings == 0 && failures == 0 {
+			return
+		}
+		m.Messages = append(m.Messages, fmt.Sprintf("⚠️  Environment check: %d failure(s), %d warning(s) - run /doctor for details", failures, warnings))
+		for _, r := range msg.Results {
+			if r.Status == doctor.Fail {
+				m.AddResult(fmt.Sprintf("%s %s: %s", r.Status.Icon(), r.Name, r.Detail))
+			}
+		}
+		return
+	}
+
+	m.Messages = append(m.Messages, fmt.Sprintf("🩺 Environment report: %d passed, %d warning(s), %d failure(s)", passed, warnings, failures))
+	for _, r := range msg.Results {
+		m.AddResult(fmt.Sprintf("%s %s: %s", r.Status.Icon(), r.Name, r.Detail))
+		if r.Hint != "" && r.Status != doctor.Pass {
+			m.AddResult("   → " + r.Hint)
+		}
+	}
+}
+
+func doctorCmd(startup bool) tea.Cmd {
+	return func() tea.Msg {
+		return models.DoctorMsg{Results: doctor.Run(), Startup: startup}
+	}
+}
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleFinish cleans up after the current branch's pull request merged:
+// it switches to the base branch, pulls, deletes the branch locally and on
+// the remote and closes the linked issue. Uncommitted or unpushed work on
+// the branch makes it refuse before touching anything. In dry-run mode the
+// checks still run but the steps are only reported.
+func HandleFinish(m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, "/finish")
+	resetInput(m)
+
+	if err := requireExecutable("gh"); err != nil {
+		return fail(err, m)
+	}
+	status, err := git.LoadStatus()
+	if err != nil {
+		return fail(err, m)
+	}
+	m.Git = status
+
+	if status.Detached() {
+		return fail(fmt.Errorf("HEAD is detached - switch to the branch to finish"), m)
+	}
+	if tracked := len(status.Files) - len(status.Untracked()); tracked > 0 {
+		return fail(fmt.Errorf("%d file(s) have uncommitted changes - commit or stash them first", tracked), m)
+	}
+	if status.HasUpstream && status.Ahead > 0 {
+		return fail(fmt.Errorf("%d commit(s) on %s are not pushed - push them first", status.Ahead, status.Branch), m)
+	}
+
+	m.AddResult(fmt.Sprintf("Checking the pull request for %s...", status.Branch))
+	root, branch, dryRun := status.Root, status.Branch, m.Overrides.DryRun
+	return func() tea.Msg {
+		steps, err := finishBranch(root, branch, dryRun)
+		return models.FinishMsg{Branch: branch, Steps: steps, Err: err}
+	}
+}
+
+// ReportFinish adds the cleanup steps to history
+func ReportFinish(msg models.FinishMsg, m *models.Model) tea.Cmd {
+	for _, step := range msg.Steps {
+		m.AddResult(step)
+	}
+	if msg.Err != nil {
+		m.AddResult(fmt.Sprintf("❌ %v", msg.Err))
+	}
+	// The branch is gone, so is its pull request
+	return tea.Batch(AdvanceScript(m, msg.Err), RefreshStatusBar(m, true))
+}
+
+// finishBranch runs the cleanup in the background. Failing to pull,
+// delete the remote branch or close the issue is reported as a warning
+// since the rest of the cleanup still applies. With dryRun it stops after
+// the checks and lists what it would do.
+func finishBranch(root, branch string, dryRun bool) ([]string, error) {
+	pr, err := github.LatestPullRequest(branch)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, fmt.Errorf("no pull request found for %s", branch)
+	}
+	if pr.State != "MERGED" {
+		return nil, fmt.Errorf("pull request #%d is %s, not merged - nothing to finish yet", pr.Number, strings.ToLower(pr.State))
+	}
+
+	// Squash and rebase merges leave the branch's commits out of the base,
+	// so compare with what the pull request merged instead
+	unpushed, err := git.CommitsNotIn(root, branch, pr.HeadRefOid)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare %s with the head of pull request #%d: %v", branch, pr.Number, err)
+	}
+	if unpushed > 0 {
+		return nil, fmt.Errorf("%d commit(s) on %s are not in pull request #%d - open another pull request for them first", unpushed, branch, pr.Number)
+	}
+
+	// Deleting the branch removes its config, so read it first
+	issue := git.BranchIssue(root, branch)
+	remote, remoteName := git.BranchRemote(root, branch)
+
+	steps := []string{fmt.Sprintf("Pull request #%d %s is merged into %s", pr.Number, pr.Title, pr.BaseRefName)}
+
+	if dryRun {
+		steps = append(steps, fmt.Sprintf("🔍 Dry run: would switch to %s and pull", pr.BaseRefName),
+			"🔍 Dry run: would delete local branch "+branch)
+		if remote != "" {
+			steps = append(steps, fmt.Sprintf("🔍 Dry run: would delete remote branch %s/%s", remote, remoteName))
+		}
+		if issue > 0 {
+			steps = append(steps, fmt.Sprintf("🔍 Dry run: would close issue #%d if it is still open", issue))
+		}
+		return steps, nil
+	}
+
+	if err := git.SwitchBranch(root, pr.BaseRefName); err != nil {
+		return steps, err
+	}
+	steps = append(steps, "Switched to "+pr.BaseRefName)
+
+	if err := git.Pull(root); err != nil {
+		steps = append(steps, fmt.Sprintf("⚠️  Pull failed: %v", err))
+	} else {
+		steps = append(steps, "Pulled "+pr.BaseRefName)
+	}
+
+	// The merge may not be in the local base branch, so -d could refuse;
+	// the unpushed check above already showed nothing would be lost
+	if err := git.DeleteBranch(root, branch, true); err != nil {
+		return steps, err
+	}
+	steps = append(steps, "Deleted local branch "+branch)
+
+	if remote != "" {
+		err := git.DeleteRemoteBranch(root, remote, remoteName)
+		switch {
+		case err != nil && strings.Contains(err.Error(), "remote ref does not exist"):
+			// GitHub can delete merged branches itself
+			steps = append(steps, fmt.Sprintf("Remote branch %s/%s was already deleted", remote, remoteName))
+		case err != nil:
+			steps = append(steps, fmt.Sprintf("⚠️  Remote branch %s/%s not deleted: %v", remote, remoteName, err))
+		default:
+			steps = append(steps, fmt.Sprintf("Deleted remote branch %s/%s", remote, remoteName))
+		}
+	}
+
+	if issue > 0 {
+		steps = append(steps, closeLinkedIssue(issue, pr.Number))
+	}
+	return steps, nil
+}
+
+// closeLinkedIssue closes the branch's issue unless the merge already did
+func closeLinkedIssue(number, pr int) string {
+	issue, err := github.ViewIssue(number)
+	if err != nil {
+		return fmt.Sprintf("⚠️  Issue #%d not checked: %v", number, err)
+	}
+	if !strings.EqualFold(issue.State, "open") {
+		return fmt.Sprintf("Issue #%d was already closed", number)
+	}
+	if err := github.CloseIssue(number, fmt.Sprintf("Resolved by #%d", pr)); err != nil {
+		return fmt.Sprintf("⚠️  Issue #%d not closed: %v", number, err)
+	}
+	return fmt.Sprintf("Closed issue #%d", number)
+}
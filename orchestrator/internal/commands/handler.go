@@ -2,9 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 
+	"gemini-orchestrator/internal/control"
 	"gemini-orchestrator/internal/models"
 	"gemini-orchestrator/internal/ui"
 	"gemini-orchestrator/internal/utils"
@@ -23,47 +26,88 @@ func HandleCommand(inputValue string, m *models.Model) tea.Cmd {
 	}
 
 	// Handle /commit command
-	if strings.HasPrefix(inputValue, "/commit") {
-		context := strings.TrimPrefix(inputValue, "/commit")
-		context = strings.TrimSpace(context)
-		
-		// Add command to history
-		m.Messages = append(m.Messages, inputValue)
-		resetInput(m)
-		
-		// Execute auto-commit with context
-		command := "auto-commit"
-		if context != "" {
-			command += " " + context
-		}
-		return executeZshCommand(command)
+	if args, ok := commandArgs(inputValue, "/commit"); ok {
+		return HandleCommit(args, m)
 	}
 
 	// Handle /pr command
-	if strings.HasPrefix(inputValue, "/pr") {
-		context := strings.TrimPrefix(inputValue, "/pr")
-		context = strings.TrimSpace(context)
-		
-		// Add command to history
-		m.Messages = append(m.Messages, inputValue)
-		resetInput(m)
-		
-		// Execute auto-pr with context
-		command := "auto-pr"
-		if context != "" {
-			command += " " + context
-		}
-		return executeZshCommand(command)
+	if args, ok := commandArgs(inputValue, "/pr"); ok {
+		return HandlePR(args, m)
 	}
 
 	// Handle /issue command
-	if inputValue == "/issue" {
-		// Add command to history
-		m.Messages = append(m.Messages, inputValue)
-		resetInput(m)
-		
-		// Execute auto-issue
-		return executeZshCommand("auto-issue")
+	if args, ok := commandArgs(inputValue, "/issue"); ok {
+		return HandleIssue(args, m)
+	}
+
+	// Handle /run command
+	if args, ok := commandArgs(inputValue, "/run"); ok {
+		return HandleRun(strings.Fields(args), m)
+	}
+
+	// Handle /plugins command
+	if inputValue == "/plugins" {
+		return HandlePlugins(m)
+	}
+
+	// Handle /doctor command
+	if inputValue == "/doctor" {
+		return HandleDoctor(m)
+	}
+
+	// Handle /status command
+	if inputValue == "/status" {
+		return HandleStatus(m)
+	}
+
+	// Handle /stage command
+	if args, ok := commandArgs(inputValue, "/stage"); ok {
+		return HandleStage(args, m)
+	}
+
+	// Handle /diff command
+	if args, ok := commandArgs(inputValue, "/diff"); ok {
+		return HandleDiff(args, m)
+	}
+
+	// Handle /log command
+	if args, ok := commandArgs(inputValue, "/log"); ok {
+		return HandleLog(args, m)
+	}
+
+	// Handle /branch command
+	if args, ok := commandArgs(inputValue, "/branch"); ok {
+		return HandleBranch(args, m)
+	}
+
+	// Handle /start command
+	if args, ok := commandArgs(inputValue, "/start"); ok {
+		return HandleStart(args, m)
+	}
+
+	// Handle /finish command
+	if inputValue == "/finish" {
+		return HandleFinish(m)
+	}
+
+	// Handle /context command
+	if args, ok := commandArgs(inputValue, "/context"); ok {
+		return HandleContext(args, m)
+	}
+
+	// Handle /config command
+	if args, ok := commandArgs(inputValue, "/config"); ok {
+		return HandleConfig(args, m)
+	}
+
+	// Handle /model command
+	if args, ok := commandArgs(inputValue, "/model"); ok {
+		return HandleModel(args, m)
+	}
+
+	// Handle /dryrun command
+	if args, ok := commandArgs(inputValue, "/dryrun"); ok {
+		return HandleDryRun(args, m)
 	}
 
 	// Handle /clear command
@@ -71,14 +115,33 @@ func HandleCommand(inputValue string, m *models.Model) tea.Cmd {
 		// Clear entire display and reset to initial state
 		ui.ComposeUI(m)
 		m.Messages = []string{"/clear\n  ⎿  (no content)"}
+		// An answer still streaming belongs to the cleared conversation
+		CancelChat(m)
+		m.ChatStream = nil
+		m.ChatReply = ""
+		m.Chat = nil
 		resetInput(m)
 		return nil
 	}
 
-	// Default: add message to history
-	m.Messages = append(m.Messages, m.TextInput.Value())
-	resetInput(m)
-	return nil
+	// Handle commands provided by plugins
+	if plugin, args, ok := findPlugin(inputValue, m); ok {
+		return runPlugin(plugin, args, inputValue, m)
+	}
+
+	// Unknown commands are kept in history as before, but a script step
+	// must not pass one off as done
+	if strings.HasPrefix(inputValue, "/") {
+		m.Messages = append(m.Messages, inputValue)
+		resetInput(m)
+		if m.Script != nil && !m.Script.Waiting {
+			return fail(fmt.Errorf("%w %s", errUnknownCommand, strings.Fields(inputValue)[0]), m)
+		}
+		return nil
+	}
+
+	// Default: chat with Gemini
+	return HandleChat(inputValue, m)
 }
 
 func HandleZshCommand(inputValue string, m *models.Model) tea.Cmd {
@@ -87,21 +150,86 @@ func HandleZshCommand(inputValue string, m *models.Model) tea.Cmd {
 	resetInput(m)
 	
 	// Execute the zsh command
-	return executeZshCommand(inputValue)
+	return executeZshCommand(inputValue, m)
 }
 
-func executeZshCommand(command string) tea.Cmd {
+func executeZshCommand(command string, m *models.Model) tea.Cmd {
 	// Create the command chain: clear && reset && [command] && clear
+	// The command's exit code is preserved so failures reach the orchestrator
 	cmdString := fmt.Sprintf(`
 		clear
 		reset
 		%s
+		exit_code=$?
 		clear
-		echo "Script completed. Returning to orchestrator..."
+		if [ $exit_code -eq 0 ]; then
+			echo "Script completed. Returning to orchestrator..."
+		else
+			echo "Script failed with exit code $exit_code. Returning to orchestrator..."
+		fi
 		sleep 1
+		exit $exit_code
 	`, command)
-	
-	return tea.ExecProcess(exec.Command("zsh", "-c", cmdString), nil)
+
+	m.Publish(control.EventScriptStarted, map[string]string{"command": command})
+
+	// Remember HEAD so a commit made by the script can be reported
+	start := time.Now()
+	head := utils.HeadCommit()
+
+	return tea.ExecProcess(zshCommand(cmdString, m), func(err error) tea.Msg {
+		return models.ExecFinishedMsg{Command: command, Err: err, NewCommit: utils.NewCommitSince(head, start)}
+	})
+}
+
+// zshCommand prepares a shell that sees the session overrides
+func zshCommand(command string, m *models.Model) *exec.Cmd {
+	cmd := exec.Command("zsh", "-c", command)
+	cmd.Env = append(os.Environ(), m.Overrides.Env()...)
+	return cmd
+}
+
+// requireExecutable catches a missing script before launching a shell that fails
+func requireExecutable(name string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%s not found on PATH - run /doctor for details", name)
+	}
+	return nil
+}
+
+// fail reports err on the latest history entry and lets a running batch
+// script apply its on-error policy, as for a failed script
+func fail(err error, m *models.Model) tea.Cmd {
+	m.AddResult(fmt.Sprintf("❌ %v", err))
+	return func() tea.Msg {
+		return models.CommandFailedMsg{Err: err}
+	}
+}
+
+// commandArgs reports whether inputValue invokes name and returns its arguments
+func commandArgs(inputValue, name string) (string, bool) {
+	if inputValue == name {
+		return "", true
+	}
+	if strings.HasPrefix(inputValue, name+" ") {
+		return strings.TrimSpace(strings.TrimPrefix(inputValue, name)), true
+	}
+	return "", false
+}
+
+// scriptArgs returns the arguments for a script when a native command's
+// args ask for it: --zsh anywhere, or a flag only the script understands
+func scriptArgs(args string) (string, bool) {
+	fields := strings.Fields(args)
+	for i, field := range fields {
+		if field == "--zsh" {
+			return strings.Join(append(fields[:i:i], fields[i+1:]...), " "), true
+		}
+	}
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+		return args, true
+	}
+	return "", false
 }
 
 func resetInput(m *models.Model) {
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleIssue carries out a request about issues in natural language,
+// such as "/issue close #12 as not planned", after showing Gemini's reading
+// of it for confirmation. Bare /issue, --zsh or any auto-issue flag runs
+// the interactive auto-issue script instead.
+func HandleIssue(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/issue "+args))
+	resetInput(m)
+
+	if script, ok := scriptArgs(args); ok || args == "" {
+		if err := requireExecutable("auto-issue"); err != nil {
+			return fail(err, m)
+		}
+		return executeZshCommand(strings.TrimSpace("auto-issue "+script), m)
+	}
+
+	if err := requireExecutable("gh"); err != nil {
+		return fail(err, m)
+	}
+	repoContext, err := m.RepoContext()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	input := panels.IssueInput{
+		Request: args,
+		Context: repoContext.Text(),
+		DryRun:  m.Overrides.DryRun,
+	}
+	// Outside a repository or on a detached HEAD there is no current issue
+	if status, err := git.LoadStatus(); err == nil && !status.Detached() {
+		input.Issue = git.BranchIssue(status.Root, status.Branch)
+	}
+
+	panel := panels.NewIssuePanel(input)
+	m.Panel = panel
+	return panel.Load()
+}
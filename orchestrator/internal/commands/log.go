@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleLog opens the commit browser on HEAD, or on the given revisions or
+// range, optionally limited to commits touching the given paths
+func HandleLog(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/log "+args))
+	resetInput(m)
+
+	root, err := git.Root()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	var options git.LogOptions
+	options.Revs, options.Paths, err = revisionsAndPaths(root, strings.Fields(args))
+	if err != nil {
+		return fail(err, m)
+	}
+
+	title := "HEAD"
+	if len(options.Revs) > 0 {
+		title = strings.Join(options.Revs, " ")
+	}
+	if len(options.Paths) > 0 {
+		title += "  (" + strings.Join(options.Paths, ", ") + ")"
+	}
+	// The log runs in the panel so long histories do not block the UI
+	panel := panels.NewLogPanel(root, options, title)
+	m.Panel = panel
+	return panel.Load()
+}
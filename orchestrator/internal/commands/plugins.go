@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/plugins"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandlePlugins rescans the plugin locations and lists what was found
+func HandlePlugins(m *models.Model) tea.Cmd {
+	m.Messages = append(m.Messages, "/plugins")
+	resetInput(m)
+
+	m.Plugins = plugins.Discover(models.SlashCommands)
+	if len(m.Plugins) == 0 {
+		m.AddResult(fmt.Sprintf("No plugins found in %s, %s or %s* on PATH", plugins.RepoDir(), plugins.UserDir(), plugins.ExecutablePrefix))
+		return nil
+	}
+
+	for _, p := range m.Plugins {
+		line := fmt.Sprintf("%s (%s) %s", p.Command(), p.Source, p.Path)
+		if p.Description != "" {
+			line += " - " + p.Description
+		}
+		m.AddResult(line)
+	}
+	return nil
+}
+
+func findPlugin(inputValue string, m *models.Model) (plugins.Plugin, string, bool) {
+	for _, p := range m.Plugins {
+		if args, ok := commandArgs(inputValue, p.Command()); ok {
+			return p, args, true
+		}
+	}
+	return plugins.Plugin{}, "", false
+}
+
+// runPlugin executes a plugin, either taking over the terminal like the
+// auto-* scripts or in the background with its output added to history
+func runPlugin(p plugins.Plugin, args, inputValue string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, inputValue)
+	resetInput(m)
+
+	// Arguments are split by zsh, as for the built-in script commands
+	command := shellQuote(p.Path)
+	if args != "" {
+		command += " " + args
+	}
+
+	if p.Interactive {
+		return executeZshCommand(command, m)
+	}
+
+	cmd := zshCommand(command, m)
+	return func() tea.Msg {
+		output, err := cmd.CombinedOutput()
+		return models.PluginOutputMsg{Name: p.Name, Output: strings.TrimRight(string(output), "\n"), Err: err}
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
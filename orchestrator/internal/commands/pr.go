@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandlePR opens the pull request panel for the current branch, which
+// creates a pull request or updates the open one. Text after /pr is extra
+// context for Gemini. --zsh, or any auto-pr flag, runs the auto-pr script
+// instead.
+func HandlePR(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/pr "+args))
+	resetInput(m)
+
+	if script, ok := scriptArgs(args); ok {
+		if err := requireExecutable("auto-pr"); err != nil {
+			return fail(err, m)
+		}
+		return executeZshCommand(strings.TrimSpace("auto-pr "+script), m)
+	}
+
+	if err := requireExecutable("gh"); err != nil {
+		return fail(err, m)
+	}
+	status, err := git.LoadStatus()
+	if err != nil {
+		return fail(err, m)
+	}
+	if status.Detached() {
+		return fail(fmt.Errorf("HEAD is detached, check out a branch first"), m)
+	}
+	repoContext, err := m.RepoContext()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	panel := panels.NewPRPanel(panels.PRInput{
+		Root:    status.Root,
+		Branch:  status.Branch,
+		Context: repoContext.Text(),
+		Extra:   args,
+		Issue:   git.BranchIssue(status.Root, status.Branch),
+		DryRun:  m.Overrides.DryRun,
+	})
+	m.Panel = panel
+	return panel.Load()
+}
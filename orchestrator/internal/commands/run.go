@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gemini-orchestrator/internal/control"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/script"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errUnknownCommand fails a script step naming no command; the script
+// stops whatever its on-error policy, since the rest was likely written
+// against the misspelt one
+var errUnknownCommand = errors.New("unknown command")
+
+// HandleRun loads a batch script and starts executing its steps
+func HandleRun(args []string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/run "+strings.Join(args, " ")))
+	resetInput(m)
+
+	if m.Script != nil {
+		m.AddResult("❌ A script is already running")
+		return nil
+	}
+
+	path, vars, err := script.ParseArgs(args)
+	if err != nil {
+		m.AddResult(err.Error())
+		return nil
+	}
+
+	s, err := script.Load(path, vars)
+	if err != nil {
+		m.AddResult(fmt.Sprintf("❌ %v", err))
+		return nil
+	}
+
+	m.Script = script.NewRun(s)
+	m.AddResult(fmt.Sprintf("Running %d steps from %s", len(s.Steps), s.Name))
+	m.Publish(control.EventBatchStarted, map[string]string{"script": s.Name, "steps": strconv.Itoa(len(s.Steps))})
+	return runNextStep(m)
+}
+
+// AdvanceScript is called when an asynchronous script step finishes
+// It applies the step's on-error policy and moves on to the next step
+func AdvanceScript(m *models.Model, err error) tea.Cmd {
+	if m.Script == nil || !m.Script.Waiting {
+		return nil
+	}
+	m.Script.Waiting = false
+
+	if err == nil {
+		return runNextStep(m)
+	}
+
+	m.Script.Failed++
+	step := m.Script.Step()
+	if errors.Is(err, errUnknownCommand) {
+		return stopScript(m, fmt.Sprintf("stopped at line %d: %v", step.Line, err))
+	}
+
+	switch step.OnError {
+	case script.OnErrorContinue:
+		return runNextStep(m)
+	case script.OnErrorAsk:
+		m.Confirm = &models.Confirm{
+			Prompt: fmt.Sprintf("Line %d failed. Continue script?", step.Line),
+			OnAnswer: func(yes bool, m *models.Model) tea.Cmd {
+				if yes {
+					return runNextStep(m)
+				}
+				return stopScript(m, fmt.Sprintf("cancelled after line %d failed", step.Line))
+			},
+		}
+		return nil
+	default:
+		return stopScript(m, fmt.Sprintf("stopped at line %d: %v", step.Line, err))
+	}
+}
+
+func runNextStep(m *models.Model) tea.Cmd {
+	for {
+		step, ok := m.Script.Next()
+		if !ok {
+			return finishScript(m)
+		}
+
+		var cmd tea.Cmd
+		switch step.Kind {
+		case script.StepConfirm:
+			line := step.Line
+			m.Confirm = &models.Confirm{
+				Prompt: step.Text,
+				OnAnswer: func(yes bool, m *models.Model) tea.Cmd {
+					if yes {
+						return runNextStep(m)
+					}
+					return stopScript(m, fmt.Sprintf("cancelled at line %d", line))
+				},
+			}
+			return nil
+		case script.StepZsh:
+			cmd = HandleZshCommand(step.Text, m)
+		default:
+			cmd = HandleCommand(step.Text, m)
+		}
+
+		// Commands without a follow-up command, open panel or question
+		// completed synchronously
+		if cmd == nil && m.Panel == nil && m.Confirm == nil {
+			continue
+		}
+		m.Script.Waiting = true
+		return cmd
+	}
+}
+
+func finishScript(m *models.Model) tea.Cmd {
+	run := m.Script
+	m.Script = nil
+
+	m.Publish(control.EventBatchFinished, map[string]string{
+		"script": run.Script.Name,
+		"status": "finished",
+		"failed": strconv.Itoa(run.Failed),
+	})
+
+	if run.Failed > 0 {
+		m.Messages = append(m.Messages, fmt.Sprintf("⚠️  Script %s finished with %d failed step(s)", run.Script.Name, run.Failed))
+	} else {
+		m.Messages = append(m.Messages, fmt.Sprintf("✅ Script %s finished (%d steps)", run.Script.Name, len(run.Script.Steps)))
+	}
+	return nil
+}
+
+func stopScript(m *models.Model, reason string) tea.Cmd {
+	name := m.Script.Script.Name
+	m.Script = nil
+	m.Publish(control.EventBatchFinished, map[string]string{"script": name, "status": "stopped", "reason": reason})
+	m.Messages = append(m.Messages, fmt.Sprintf("❌ Script %s %s", name, reason))
+	return nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleModel shows or overrides the Gemini model for the rest of the session
+func HandleModel(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/model "+args))
+	resetInput(m)
+
+	switch args {
+	case "":
+		model, source := m.Config.Model(m.Overrides)
+		native, nativeSource := m.Config.NativeModel(m.Overrides)
+		if native == model {
+			m.AddResult(fmt.Sprintf("Using %s (%s) with the %s backend", model, source, m.Config.LLMBackend))
+			return nil
+		}
+		if native == "" {
+			native, nativeSource = "its default model", "LLM_MODEL unset"
+		}
+		m.AddResult(fmt.Sprintf("Scripts use %s (%s); the %s backend uses %s (%s)", model, source, m.Config.LLMBackend, native, nativeSource))
+		return nil
+	case "list":
+		backend, _, err := m.LLM()
+		if err != nil {
+			return fail(err, m)
+		}
+		return func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			names, err := backend.Models(ctx)
+			return models.ModelListMsg{Backend: backend.Name(), Models: names, Err: err}
+		}
+	case "reset", "default":
+		m.Overrides.Model = ""
+		model, source := m.Config.Model(m.Overrides)
+		m.AddResult(fmt.Sprintf("Session override cleared, using %s (%s)", model, source))
+		return nil
+	}
+
+	if err := config.ValidateModel(args); err != nil {
+		return fail(err, m)
+	}
+	m.Overrides.Model = args
+	m.AddResult(fmt.Sprintf("Scripts will use %s for this session", args))
+	return nil
+}
+
+// ReportModels lists the backend's models, marking the one in use
+func ReportModels(msg models.ModelListMsg, m *models.Model) tea.Cmd {
+	if msg.Err != nil {
+		m.AddResult(fmt.Sprintf("❌ %v", msg.Err))
+		return AdvanceScript(m, msg.Err)
+	}
+
+	current, _ := m.Config.NativeModel(m.Overrides)
+	m.AddResult(fmt.Sprintf("%d model(s) from the %s backend", len(msg.Models), msg.Backend))
+	for _, name := range msg.Models {
+		marker := "  "
+		if name == current {
+			marker = "▶ "
+		}
+		m.AddResult(marker + name)
+	}
+	return AdvanceScript(m, nil)
+}
+
+// HandleDryRun toggles dry-run mode for every script launched this session
+func HandleDryRun(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/dryrun "+args))
+	resetInput(m)
+
+	switch args {
+	case "":
+		m.AddResult(fmt.Sprintf("Dry-run is %s", onOff(m.Overrides.DryRun)))
+		return nil
+	case "on", "true":
+		m.Overrides.DryRun = true
+	case "off", "false":
+		m.Overrides.DryRun = false
+	default:
+		return fail(fmt.Errorf("usage: /dryrun on|off"), m)
+	}
+
+	m.AddResult(fmt.Sprintf("Dry-run %s for scripts launched from this session", onOff(m.Overrides.DryRun)))
+	return nil
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleStart fetches an issue in the background so ContinueStart can
+// create its branch; --assign and --no-assign answer the self-assign
+// question up front
+func HandleStart(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/start "+args))
+	resetInput(m)
+
+	assign := ""
+	var reference string
+	for _, arg := range strings.Fields(args) {
+		switch arg {
+		case "--assign":
+			assign = "yes"
+		case "--no-assign":
+			assign = "no"
+		default:
+			if reference != "" {
+				return fail(fmt.Errorf("usage: /start <issue> [--assign|--no-assign]"), m)
+			}
+			reference = arg
+		}
+	}
+	if reference == "" {
+		return fail(fmt.Errorf("usage: /start <issue> [--assign|--no-assign]"), m)
+	}
+
+	number, err := github.ParseIssueNumber(reference)
+	if err != nil {
+		return fail(err, m)
+	}
+	if err := requireExecutable("gh"); err != nil {
+		return fail(err, m)
+	}
+	if _, err := git.Root(); err != nil {
+		return fail(err, m)
+	}
+
+	m.AddResult(fmt.Sprintf("Fetching issue #%d...", number))
+	return func() tea.Msg {
+		issue, err := github.ViewIssue(number)
+		if err != nil {
+			return models.StartIssueMsg{Err: err}
+		}
+		// Without the login the issue is simply offered for assignment
+		self, _ := github.CurrentUser()
+		return models.StartIssueMsg{Issue: issue, Self: self, Assign: assign}
+	}
+}
+
+// ContinueStart creates or switches to the issue's branch, links the issue
+// to it for /commit and /pr, and offers to assign the issue. A dry run
+// reports those steps instead.
+func ContinueStart(msg models.StartIssueMsg, m *models.Model) tea.Cmd {
+	if msg.Err != nil {
+		return fail(msg.Err, m)
+	}
+	issue := msg.Issue
+	if !strings.EqualFold(issue.State, "open") {
+		return fail(fmt.Errorf("issue #%d is %s", issue.Number, strings.ToLower(issue.State)), m)
+	}
+
+	root, err := git.Root()
+	if err != nil {
+		return fail(err, m)
+	}
+
+	branchType := "feat"
+	if issue.IsBug() {
+		branchType = "fix"
+	}
+	name := m.Config.BranchName(branchType, issue.Title)
+	if err := git.ValidateBranchName(name); err != nil {
+		return fail(fmt.Errorf("cannot name a branch after %q: %v", issue.Title, err), m)
+	}
+
+	m.AddResult(fmt.Sprintf("#%d %s", issue.Number, issue.Title))
+	if m.Overrides.DryRun {
+		return reportStart(msg, root, name, m)
+	}
+	if git.BranchExists(root, name) {
+		if err := git.SwitchBranch(root, name); err != nil {
+			return fail(err, m)
+		}
+		m.AddResult("Switched to existing branch " + name)
+	} else {
+		if err := git.CreateBranch(root, name); err != nil {
+			return fail(err, m)
+		}
+		m.AddResult("Created branch " + name)
+	}
+
+	if err := git.SetBranchIssue(root, name, issue.Number); err != nil {
+		return fail(err, m)
+	}
+	m.AddResult(fmt.Sprintf("/commit and /pr will reference resolves #%d", issue.Number))
+
+	refresh := RefreshStatusBar(m, true)
+	switch {
+	case msg.Self != "" && issue.AssignedTo(msg.Self):
+		m.AddResult("Already assigned to " + msg.Self)
+		return tea.Batch(AdvanceScript(m, nil), refresh)
+	case msg.Assign == "yes":
+		return tea.Batch(assignIssueCmd(issue.Number), refresh)
+	case msg.Assign == "no":
+		return tea.Batch(AdvanceScript(m, nil), refresh)
+	}
+
+	m.Confirm = &models.Confirm{
+		Prompt: fmt.Sprintf("Assign issue #%d to yourself?", issue.Number),
+		OnAnswer: func(yes bool, m *models.Model) tea.Cmd {
+			if yes {
+				return assignIssueCmd(issue.Number)
+			}
+			return AdvanceScript(m, nil)
+		},
+	}
+	return refresh
+}
+
+// reportStart lists what ContinueStart would do without doing it
+func reportStart(msg models.StartIssueMsg, root, name string, m *models.Model) tea.Cmd {
+	issue := msg.Issue
+	if git.BranchExists(root, name) {
+		m.AddResult("🔍 Dry run: would switch to existing branch " + name)
+	} else {
+		m.AddResult("🔍 Dry run: would create branch " + name)
+	}
+	m.AddResult(fmt.Sprintf("🔍 Dry run: would link issue #%d to %s for /commit and /pr", issue.Number, name))
+
+	switch {
+	case msg.Self != "" && issue.AssignedTo(msg.Self):
+		m.AddResult("Already assigned to " + msg.Self)
+	case msg.Assign == "no":
+	case msg.Assign == "yes":
+		m.AddResult(fmt.Sprintf("🔍 Dry run: would assign issue #%d to you", issue.Number))
+	default:
+		m.AddResult(fmt.Sprintf("🔍 Dry run: would offer to assign issue #%d to you", issue.Number))
+	}
+	return AdvanceScript(m, nil)
+}
+
+// ReportIssueAssigned adds the outcome of a self-assignment to history
+func ReportIssueAssigned(msg models.IssueAssignedMsg, m *models.Model) tea.Cmd {
+	if msg.Err != nil {
+		m.AddResult(fmt.Sprintf("❌ %v", msg.Err))
+	} else {
+		m.AddResult(fmt.Sprintf("Assigned issue #%d to you", msg.Number))
+	}
+	return AdvanceScript(m, msg.Err)
+}
+
+func assignIssueCmd(number int) tea.Cmd {
+	return func() tea.Msg {
+		return models.IssueAssignedMsg{Number: number, Err: github.AssignIssueToSelf(number)}
+	}
+}
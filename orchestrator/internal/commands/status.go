@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/panels"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HandleStatus opens the staging panel on a freshly loaded status
+func HandleStatus(m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, "/status")
+	resetInput(m)
+
+	status, err := git.LoadStatus()
+	if err != nil {
+		return fail(err, m)
+	}
+	m.Git = status
+	m.Panel = panels.NewStatusPanel()
+	return nil
+}
+
+// HandleStage walks the hunks of every file with unstaged changes, or with
+// --staged the staged ones to unstage them, optionally limited to paths
+func HandleStage(args string, m *models.Model) tea.Cmd {
+	// Add command to history
+	m.Messages = append(m.Messages, strings.TrimSpace("/stage "+args))
+	resetInput(m)
+
+	status, err := git.LoadStatus()
+	if err != nil {
+		return fail(err, m)
+	}
+	m.Git = status
+
+	staged := false
+	var paths []string
+	for _, arg := range strings.Fields(args) {
+		if arg == "--staged" || arg == "--cached" {
+			staged = true
+			continue
+		}
+		paths = append(paths, rootRelative(status.Root, arg))
+	}
+
+	if len(paths) == 0 {
+		files := status.Unstaged()
+		if staged {
+			files = status.Staged()
+		}
+		for _, f := range files {
+			// Conflicts must be resolved before parts of them can be staged
+			if f.Kind != git.Unmerged {
+				paths = append(paths, f.Path)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		if staged {
+			return fail(fmt.Errorf("nothing staged"), m)
+		}
+		return fail(fmt.Errorf("no unstaged changes to tracked files"), m)
+	}
+
+	panel := panels.NewHunkPanel(status.Root, paths, staged, nil)
+	m.Panel = panel
+	return panel.Load()
+}
+
+// rootRelative turns a path given relative to the current directory into
+// one relative to root, as git -C root expects
+func rootRelative(root, path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if relative, err := filepath.Rel(root, abs); err == nil {
+			return filepath.ToSlash(relative)
+		}
+	}
+	return path
+}
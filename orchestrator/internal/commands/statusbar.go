@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"os/exec"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RefreshStatusBar reloads the repository state shown in the status bar
+// When forcePR is set the pull request is looked up again even if the branch
+// did not change, e.g. after a script that may have created one
+func RefreshStatusBar(m *models.Model, forcePR bool) tea.Cmd {
+	if forcePR {
+		m.PullRequestBranch = ""
+	}
+	return git.RefreshStatus()
+}
+
+// UpdateGitStatus stores a refreshed status and looks up the pull request
+// when the branch changed
+func UpdateGitStatus(msg git.StatusMsg, m *models.Model) tea.Cmd {
+	if msg.Err != nil {
+		m.Git = nil
+		return nil
+	}
+	m.Git = msg.Status
+
+	branch := m.Git.Branch
+	if branch == m.PullRequestBranch {
+		return nil
+	}
+	m.PullRequestBranch = branch
+	m.PullRequest = nil
+	if branch == "" {
+		return nil
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil
+	}
+	return github.LookupPullRequest(branch)
+}
+
+// UpdatePullRequest stores the pull request found for the current branch
+// A failed lookup (offline, not logged in) leaves the status bar without one
+func UpdatePullRequest(msg github.PullRequestMsg, m *models.Model) {
+	if msg.Branch != m.PullRequestBranch || msg.Err != nil {
+		return
+	}
+	m.PullRequest = msg.PullRequest
+}
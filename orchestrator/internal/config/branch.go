@@ -0,0 +1,41 @@
+package config
+
+import "strings"
+
+// BranchTypes are the branch categories with a configurable prefix
+var BranchTypes = []string{"feat", "fix", "docs", "refactor"}
+
+// maxBranchSlug keeps generated branch names readable
+const maxBranchSlug = 50
+
+// BranchName builds a branch name from free text with the prefix for
+// branchType and the configured naming style, e.g. "Retry on 502 errors"
+// becomes "fix/retry-on-502-errors" in kebab-case
+func (c *Config) BranchName(branchType, description string) string {
+	return c.BranchPrefix(branchType) + Slug(description, c.BranchNamingStyle)
+}
+
+// Slug reduces text to lowercase letters and digits joined in style,
+// dropping words past maxBranchSlug characters
+func Slug(text, style string) string {
+	separator := "-"
+	if style == "snake_case" {
+		separator = "_"
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + separator + word
+		}
+		if len(next) > maxBranchSlug && slug != "" {
+			break
+		}
+		slug = next
+	}
+	return slug
+}
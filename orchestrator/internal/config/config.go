@@ -0,0 +1,325 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Configuration keys understood by the scripts
+const (
+	KeyGeminiModel          = "GEMINI_MODEL"
+	KeyAutoStage            = "AUTO_STAGE"
+	KeyAutoPR               = "AUTO_PR"
+	KeyAutoBranch           = "AUTO_BRANCH"
+	KeyAutoPush             = "AUTO_PUSH"
+	KeySkipEnvInfo          = "SKIP_ENV_INFO"
+	KeyAutoPushAfterPR      = "AUTO_PUSH_AFTER_PR"
+	KeyBranchPrefixFeat     = "BRANCH_PREFIX_FEAT"
+	KeyBranchPrefixFix      = "BRANCH_PREFIX_FIX"
+	KeyBranchPrefixDocs     = "BRANCH_PREFIX_DOCS"
+	KeyBranchPrefixRefactor = "BRANCH_PREFIX_REFACTOR"
+	KeyBranchNamingStyle    = "BRANCH_NAMING_STYLE"
+
+	// Only read by the orchestrator's native Gemini features
+	KeyLLMBackend  = "LLM_BACKEND"
+	KeyLLMEndpoint = "LLM_ENDPOINT"
+	KeyLLMModel    = "LLM_MODEL"
+)
+
+// Defaults mirrors the DEFAULT_* values in config/config_loader.zsh, plus
+// the orchestrator's LLM keys; an empty LLM_ENDPOINT or LLM_MODEL uses the
+// backend's own
+var Defaults = map[string]string{
+	KeyGeminiModel:          "gemini-2.5-flash",
+	KeyAutoStage:            "false",
+	KeyAutoPR:               "false",
+	KeyAutoBranch:           "false",
+	KeyAutoPush:             "false",
+	KeySkipEnvInfo:          "false",
+	KeyAutoPushAfterPR:      "false",
+	KeyBranchPrefixFeat:     "feat/",
+	KeyBranchPrefixFix:      "fix/",
+	KeyBranchPrefixDocs:     "docs/",
+	KeyBranchPrefixRefactor: "refactor/",
+	KeyBranchNamingStyle:    "kebab-case",
+	KeyLLMBackend:           "gemini-cli",
+	KeyLLMEndpoint:          "",
+	KeyLLMModel:             "",
+}
+
+// Keys lists the known keys in the order of default.gemini-config
+var Keys = []string{
+	KeyGeminiModel,
+	KeyAutoStage,
+	KeyAutoPR,
+	KeyAutoBranch,
+	KeyAutoPush,
+	KeySkipEnvInfo,
+	KeyAutoPushAfterPR,
+	KeyBranchPrefixFeat,
+	KeyBranchPrefixFix,
+	KeyBranchPrefixDocs,
+	KeyBranchPrefixRefactor,
+	KeyBranchNamingStyle,
+	KeyLLMBackend,
+	KeyLLMEndpoint,
+	KeyLLMModel,
+}
+
+// Source identifies the tier an effective value came from
+type Source string
+
+const (
+	SourceBuiltin Source = "built-in" // DEFAULT_* values compiled into the loader
+	SourceDefault Source = "default"  // <scripts>/config/default.gemini-config
+	SourceUser    Source = "user"     // ~/.config/gemini-cli/.gemini-config
+	SourceRepo    Source = "repo"     // ./.gemini-config
+)
+
+// Entry is a single KEY=value assignment
+type Entry struct {
+	Key    string
+	Value  string
+	Source Source
+	Path   string
+	Line   int
+}
+
+// Warning describes a line load_config_file silently skips
+type Warning struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s:%d: %s", w.Path, w.Line, w.Message)
+}
+
+// Paths locates the three configuration tiers; empty paths are skipped
+type Paths struct {
+	Default string
+	User    string
+	Repo    string
+}
+
+// Config is the effective configuration after applying all tiers
+type Config struct {
+	GeminiModel          string
+	AutoStage            bool
+	AutoPR               bool
+	AutoBranch           bool
+	AutoPush             bool
+	SkipEnvInfo          bool
+	AutoPushAfterPR      bool
+	BranchPrefixFeat     string
+	BranchPrefixFix      string
+	BranchPrefixDocs     string
+	BranchPrefixRefactor string
+	BranchNamingStyle    string
+	LLMBackend           string
+	LLMEndpoint          string
+	LLMModel             string
+
+	// Entries holds every effective key, including unknown ones, with provenance
+	Entries  map[string]Entry
+	Warnings []Warning
+	Paths    Paths
+}
+
+// DefaultPaths returns the tiers load_gemini_config reads when run from the
+// current directory by scripts installed next to this binary
+func DefaultPaths() Paths {
+	var paths Paths
+
+	if dir := ScriptDir(); dir != "" {
+		paths.Default = filepath.Join(dir, "config", "default.gemini-config")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths.User = filepath.Join(home, ".config", "gemini-cli", ".gemini-config")
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		paths.Repo = filepath.Join(cwd, ".gemini-config")
+	}
+	return paths
+}
+
+// ScriptDir finds the gemini-cli-scripts checkout: the one this binary was
+// built in, otherwise the one auto-commit on PATH points to
+func ScriptDir() string {
+	var candidates []string
+	if execPath, err := os.Executable(); err == nil {
+		candidates = append(candidates, execPath)
+	}
+	if scriptPath, err := exec.LookPath("auto-commit"); err == nil {
+		candidates = append(candidates, scriptPath)
+	}
+
+	for _, path := range candidates {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		// The binary lives in <repo>/orchestrator, the scripts in <repo>
+		for _, dir := range []string{filepath.Dir(filepath.Dir(path)), filepath.Dir(path)} {
+			if _, err := os.Stat(filepath.Join(dir, "config", "config_loader.zsh")); err == nil {
+				return dir
+			}
+		}
+	}
+	return ""
+}
+
+// Load applies the built-in defaults, then the default, user and repository
+// files, later tiers overriding earlier ones as in load_gemini_config
+func Load(paths Paths) (*Config, error) {
+	c := &Config{Entries: map[string]Entry{}, Paths: paths}
+
+	for key, value := range Defaults {
+		c.Entries[key] = Entry{Key: key, Value: value, Source: SourceBuiltin}
+	}
+
+	tiers := []struct {
+		source Source
+		path   string
+	}{
+		{SourceDefault, paths.Default},
+		{SourceUser, paths.User},
+		{SourceRepo, paths.Repo},
+	}
+
+	for _, tier := range tiers {
+		if tier.path == "" {
+			continue
+		}
+		entries, warnings, err := ParseFile(tier.path, tier.source)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			c.Entries[entry.Key] = entry
+		}
+		c.Warnings = append(c.Warnings, warnings...)
+	}
+
+	c.apply()
+	return c, nil
+}
+
+// ParseFile reads a single .gemini-config file
+func ParseFile(path string, source Source) ([]Entry, []Warning, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	entries, warnings, err := Parse(file)
+	for i := range entries {
+		entries[i].Source = source
+		entries[i].Path = path
+	}
+	for i := range warnings {
+		warnings[i].Path = path
+	}
+	return entries, warnings, err
+}
+
+// Parse follows load_config_file: the key is everything before the first
+// "=", both sides are trimmed, and lines that are blank, start with "#" or
+// lack a key or value are skipped. Surrounding double quotes are removed as
+// the shell does when the value is assigned. A final line without a newline
+// is read, as the loop in load_config_file does.
+func Parse(r io.Reader) ([]Entry, []Warning, error) {
+	var entries []Entry
+	var warnings []Warning
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		key, value, found := strings.Cut(line, "=")
+
+		// Skip empty lines and comments
+		trimmedKey := strings.TrimSpace(key)
+		if trimmedKey == "" && !found {
+			continue
+		}
+		if strings.HasPrefix(trimmedKey, "#") {
+			continue
+		}
+
+		value = unquote(strings.TrimSpace(value))
+		switch {
+		case !found:
+			warnings = append(warnings, Warning{Line: lineNumber, Message: fmt.Sprintf("no '=' in %q", strings.TrimSpace(line))})
+		case trimmedKey == "":
+			warnings = append(warnings, Warning{Line: lineNumber, Message: "missing key before '='"})
+		case value == "":
+			warnings = append(warnings, Warning{Line: lineNumber, Message: fmt.Sprintf("%s has no value", trimmedKey)})
+		default:
+			entries = append(entries, Entry{Key: trimmedKey, Value: value, Line: lineNumber})
+		}
+	}
+	return entries, warnings, scanner.Err()
+}
+
+// Value returns the effective value of key, or "" when unset
+func (c *Config) Value(key string) string {
+	return c.Entries[key].Value
+}
+
+// BranchPrefix mirrors get_branch_prefix
+func (c *Config) BranchPrefix(branchType string) string {
+	switch branchType {
+	case "feat", "feature":
+		return c.BranchPrefixFeat
+	case "fix", "bugfix":
+		return c.BranchPrefixFix
+	case "docs", "documentation":
+		return c.BranchPrefixDocs
+	case "refactor":
+		return c.BranchPrefixRefactor
+	default:
+		return branchType + "/"
+	}
+}
+
+// IsTrue mirrors is_config_true
+func IsTrue(value string) bool {
+	return value == "true" || value == "1" || value == "yes"
+}
+
+// apply copies the effective entries into the typed fields
+func (c *Config) apply() {
+	c.GeminiModel = c.Value(KeyGeminiModel)
+	c.AutoStage = IsTrue(c.Value(KeyAutoStage))
+	c.AutoPR = IsTrue(c.Value(KeyAutoPR))
+	c.AutoBranch = IsTrue(c.Value(KeyAutoBranch))
+	c.AutoPush = IsTrue(c.Value(KeyAutoPush))
+	c.SkipEnvInfo = IsTrue(c.Value(KeySkipEnvInfo))
+	c.AutoPushAfterPR = IsTrue(c.Value(KeyAutoPushAfterPR))
+	c.BranchPrefixFeat = c.Value(KeyBranchPrefixFeat)
+	c.BranchPrefixFix = c.Value(KeyBranchPrefixFix)
+	c.BranchPrefixDocs = c.Value(KeyBranchPrefixDocs)
+	c.BranchPrefixRefactor = c.Value(KeyBranchPrefixRefactor)
+	c.BranchNamingStyle = c.Value(KeyBranchNamingStyle)
+	c.LLMBackend = c.Value(KeyLLMBackend)
+	c.LLMEndpoint = c.Value(KeyLLMEndpoint)
+	c.LLMModel = c.Value(KeyLLMModel)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
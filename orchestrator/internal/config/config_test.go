@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// These tests mirror test/test_config_system.zsh so both loaders stay in step
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, ".gemini-config")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func load(t *testing.T, paths Paths) *Config {
+	t.Helper()
+	c, err := Load(paths)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	return c
+}
+
+func assertEquals(t *testing.T, expected, actual, name string) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("%s: expected %q, got %q", name, expected, actual)
+	}
+}
+
+func TestConfigLoaderBasics(t *testing.T) {
+	c := load(t, Paths{
+		Default: filepath.Join("..", "..", "..", "config", "default.gemini-config"),
+		User:    filepath.Join(t.TempDir(), ".gemini-config"),
+		Repo:    filepath.Join(t.TempDir(), ".gemini-config"),
+	})
+
+	assertEquals(t, "gemini-2.5-flash", c.GeminiModel, "Default model loaded")
+	if c.AutoStage || c.AutoPR {
+		t.Errorf("Default auto_stage/auto_pr should be false")
+	}
+	assertEquals(t, "feat/", c.BranchPrefixFeat, "Default feat prefix loaded")
+	assertEquals(t, "feat/", c.BranchPrefix("feat"), "BranchPrefix() returns correct feat prefix")
+	assertEquals(t, "fix/", c.BranchPrefix("fix"), "BranchPrefix() returns correct fix prefix")
+	assertEquals(t, string(SourceDefault), string(c.Entries[KeyGeminiModel].Source), "Model comes from default.gemini-config")
+}
+
+func TestIsTrue(t *testing.T) {
+	for _, value := range []string{"true", "1", "yes"} {
+		if !IsTrue(value) {
+			t.Errorf("IsTrue(%q) should be true", value)
+		}
+	}
+	for _, value := range []string{"false", "0", "no", "TRUE", ""} {
+		if IsTrue(value) {
+			t.Errorf("IsTrue(%q) should be false", value)
+		}
+	}
+}
+
+func TestConfigPriority(t *testing.T) {
+	user := writeConfig(t, t.TempDir(), "GEMINI_MODEL=gemini-1.5-pro\nAUTO_STAGE=true\nAUTO_PUSH=true\n")
+	repo := writeConfig(t, t.TempDir(), "GEMINI_MODEL=gemini-1.5-flash\nAUTO_STAGE=false\n")
+
+	c := load(t, Paths{User: user, Repo: repo})
+
+	assertEquals(t, "gemini-1.5-flash", c.GeminiModel, "Repository config overrides user config for model")
+	if c.AutoStage {
+		t.Errorf("Repository config should override user config for auto_stage")
+	}
+	if !c.AutoPush {
+		t.Errorf("User config should override system default for auto_push")
+	}
+	assertEquals(t, string(SourceRepo), string(c.Entries[KeyGeminiModel].Source), "Model provenance")
+	assertEquals(t, string(SourceUser), string(c.Entries[KeyAutoPush].Source), "Auto push provenance")
+	assertEquals(t, string(SourceBuiltin), string(c.Entries[KeyAutoPR].Source), "Auto PR provenance")
+}
+
+func TestInvalidConfigHandling(t *testing.T) {
+	repo := writeConfig(t, t.TempDir(), `# Valid config
+GEMINI_MODEL=valid-model
+
+# Invalid lines (should be ignored)
+INVALID LINE WITHOUT EQUALS
+=EQUALS_AT_START
+KEY_WITH_NO_VALUE=
+
+# Another valid line
+AUTO_STAGE=true
+`)
+
+	c := load(t, Paths{Repo: repo})
+
+	assertEquals(t, "valid-model", c.GeminiModel, "Valid config values are loaded despite invalid lines")
+	if !c.AutoStage {
+		t.Errorf("Multiple valid values should be loaded")
+	}
+	if _, ok := c.Entries["KEY_WITH_NO_VALUE"]; ok {
+		t.Errorf("Keys without values should be skipped")
+	}
+	if len(c.Warnings) != 3 {
+		t.Errorf("Expected 3 warnings for invalid lines, got %v", c.Warnings)
+	}
+}
+
+func TestBranchPrefixConfig(t *testing.T) {
+	repo := writeConfig(t, t.TempDir(), `BRANCH_PREFIX_FEAT=feature/
+BRANCH_PREFIX_FIX=bugfix/
+BRANCH_PREFIX_DOCS=documentation/
+BRANCH_PREFIX_REFACTOR=refactor/
+`)
+
+	c := load(t, Paths{Repo: repo})
+
+	assertEquals(t, "feature/", c.BranchPrefix("feat"), "Custom feat prefix loaded")
+	assertEquals(t, "bugfix/", c.BranchPrefix("fix"), "Custom fix prefix loaded")
+	assertEquals(t, "documentation/", c.BranchPrefix("docs"), "Custom docs prefix loaded")
+	assertEquals(t, "unknown/", c.BranchPrefix("unknown"), "Unknown prefix falls back correctly")
+}
+
+func TestSpecialCharacters(t *testing.T) {
+	repo := writeConfig(t, t.TempDir(), `GEMINI_MODEL=model-with-dashes-and_underscores
+BRANCH_PREFIX_FEAT=feature/with-dashes/
+`)
+
+	c := load(t, Paths{Repo: repo})
+
+	assertEquals(t, "model-with-dashes-and_underscores", c.GeminiModel, "Model with special characters loaded")
+	assertEquals(t, "feature/with-dashes/", c.BranchPrefixFeat, "Branch prefix with special characters loaded")
+}
+
+func TestWhitespaceAndQuotes(t *testing.T) {
+	repo := writeConfig(t, t.TempDir(), "  GEMINI_MODEL  =  \"quoted-model\"  \nAUTO_PUSH=yes")
+
+	c := load(t, Paths{Repo: repo})
+
+	assertEquals(t, "quoted-model", c.GeminiModel, "Whitespace is trimmed and quotes removed")
+	if !c.AutoPush {
+		t.Errorf("Final line without newline should be loaded, as load_config_file does")
+	}
+}
+
+func TestNativeModel(t *testing.T) {
+	repo := writeConfig(t, t.TempDir(), "GEMINI_MODEL=gemini-2.5-pro\nLLM_MODEL=llama3.2\n")
+	override := Overrides{Model: "gemini-2.5-flash-lite"}
+
+	c := load(t, Paths{Repo: repo})
+	for _, backend := range []string{"", "gemini-cli", "gemini-api"} {
+		c.LLMBackend = backend
+		model, _ := c.NativeModel(Overrides{})
+		assertEquals(t, "gemini-2.5-pro", model, backend+" uses GEMINI_MODEL")
+		model, _ = c.NativeModel(override)
+		assertEquals(t, "gemini-2.5-flash-lite", model, backend+" uses the session override")
+	}
+
+	c.LLMBackend = "openai"
+	model, source := c.NativeModel(override)
+	assertEquals(t, "llama3.2", model, "openai uses LLM_MODEL, not the Gemini override")
+	assertEquals(t, string(SourceRepo), string(source), "LLM_MODEL comes from the repository config")
+
+	c = load(t, Paths{Repo: writeConfig(t, t.TempDir(), "LLM_BACKEND=openai\n")})
+	model, _ = c.NativeModel(Overrides{})
+	assertEquals(t, "", model, "openai without LLM_MODEL leaves the choice to the server")
+}
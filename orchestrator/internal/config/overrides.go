@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables the scripts read for session overrides
+const (
+	EnvModel  = "GEMINI_CLI_MODEL"   // Replaces GEMINI_MODEL from every tier in load_gemini_config
+	EnvDryRun = "GEMINI_CLI_DRY_RUN" // Sets dry_run=true in the auto-* scripts
+)
+
+// SourceSession marks a value overridden for the current orchestrator session
+const SourceSession Source = "session"
+
+// Overrides are session-scoped settings that take precedence over the files
+// without changing them
+type Overrides struct {
+	Model  string // Empty uses GEMINI_MODEL
+	DryRun bool
+}
+
+// OverridesFromEnv picks up overrides the orchestrator itself was started with
+func OverridesFromEnv() Overrides {
+	return Overrides{
+		Model:  strings.TrimSpace(os.Getenv(EnvModel)),
+		DryRun: IsTrue(os.Getenv(EnvDryRun)),
+	}
+}
+
+// Env returns the assignments to add to a launched script's environment.
+// Unset overrides are exported empty so they cannot leak in from the
+// orchestrator's own environment after being cleared.
+func (o Overrides) Env() []string {
+	dryRun := "false"
+	if o.DryRun {
+		dryRun = "true"
+	}
+	return []string{EnvModel + "=" + o.Model, EnvDryRun + "=" + dryRun}
+}
+
+// Active reports whether any override is set
+func (o Overrides) Active() bool {
+	return o.Model != "" || o.DryRun
+}
+
+// String summarises the active overrides, e.g. "model: gemini-2.5-pro • dry-run"
+func (o Overrides) String() string {
+	var parts []string
+	if o.Model != "" {
+		parts = append(parts, "model: "+o.Model)
+	}
+	if o.DryRun {
+		parts = append(parts, "dry-run")
+	}
+	return strings.Join(parts, " • ")
+}
+
+// ValidateModel checks a model name before it is used as an override
+func ValidateModel(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n\"'") {
+		return fmt.Errorf("invalid model name %q", name)
+	}
+	return nil
+}
+
+// Model returns the model the scripts will use under the overrides
+func (c *Config) Model(o Overrides) (string, Source) {
+	if o.Model != "" {
+		return o.Model, SourceSession
+	}
+	return c.GeminiModel, c.Entries[KeyGeminiModel].Source
+}
+
+// NativeModel returns the model the orchestrator's own LLM requests use.
+// The Gemini backends share the scripts' model. Other backends would not
+// know a Gemini model name, so they use LLM_MODEL, where empty lets the
+// backend choose.
+func (c *Config) NativeModel(o Overrides) (string, Source) {
+	switch c.LLMBackend {
+	case "", "gemini-cli", "gemini-api":
+		return c.Model(o)
+	}
+	return c.LLMModel, c.Entries[KeyLLMModel].Source
+}
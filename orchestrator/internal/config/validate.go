@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Boolean keys accept the values is_config_true understands, plus their negations
+var booleanKeys = map[string]bool{
+	KeyAutoStage:       true,
+	KeyAutoPR:          true,
+	KeyAutoBranch:      true,
+	KeyAutoPush:        true,
+	KeySkipEnvInfo:     true,
+	KeyAutoPushAfterPR: true,
+}
+
+var booleanValues = []string{"true", "false", "1", "0", "yes", "no"}
+
+// NamingStyles lists the supported BRANCH_NAMING_STYLE values
+var NamingStyles = []string{"kebab-case", "snake_case"}
+
+// LLMBackends lists the supported LLM_BACKEND values
+var LLMBackends = []string{"gemini-cli", "gemini-api", "openai", "fake"}
+
+// IsKnown reports whether key is read by the scripts or the orchestrator
+func IsKnown(key string) bool {
+	_, ok := Defaults[key]
+	return ok
+}
+
+// IsBoolean reports whether key holds a boolean flag
+func IsBoolean(key string) bool {
+	return booleanKeys[key]
+}
+
+// Validate checks a value before it is written to a config file
+func Validate(key, value string) error {
+	if !IsKnown(key) {
+		if suggestion := Suggest(key); suggestion != "" {
+			return fmt.Errorf("unknown key %s (did you mean %s?)", key, suggestion)
+		}
+		return fmt.Errorf("unknown key %s", key)
+	}
+	if strings.ContainsAny(value, "\n\r") {
+		return fmt.Errorf("%s must be a single line", key)
+	}
+
+	switch {
+	case booleanKeys[key]:
+		if !slices.Contains(booleanValues, value) {
+			return fmt.Errorf("%s must be one of %s", key, strings.Join(booleanValues, ", "))
+		}
+	case key == KeyBranchNamingStyle:
+		if !slices.Contains(NamingStyles, value) {
+			return fmt.Errorf("%s must be one of %s", key, strings.Join(NamingStyles, ", "))
+		}
+	case key == KeyLLMBackend:
+		if !slices.Contains(LLMBackends, value) {
+			return fmt.Errorf("%s must be one of %s", key, strings.Join(LLMBackends, ", "))
+		}
+	case strings.ContainsAny(value, " \t"):
+		return fmt.Errorf("%s must not contain whitespace", key)
+	}
+	return nil
+}
+
+// Problems describes unknown keys and invalid values among the effective entries
+func (c *Config) Problems() map[string]string {
+	problems := map[string]string{}
+	for key, entry := range c.Entries {
+		if err := Validate(key, entry.Value); err != nil {
+			problems[key] = err.Error()
+		}
+	}
+	return problems
+}
+
+// Suggest returns the known key closest to a misspelled one, or ""
+func Suggest(key string) string {
+	best, bestDistance := "", 4 // Only suggest keys within a few edits
+	upper := strings.ToUpper(key)
+	for _, known := range Keys {
+		if distance := editDistance(upper, known); distance < bestDistance {
+			best, bestDistance = known, distance
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
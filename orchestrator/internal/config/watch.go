@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PollInterval is how often Watch checks the configuration files. Polling
+// keeps working when editors replace files or a checkout swaps the repo tier.
+const PollInterval = time.Second
+
+// fileState is what Watch compares between polls; a missing file is the zero value
+type fileState struct {
+	size    int64
+	modTime int64
+}
+
+// Fingerprint records the state of every configured tier file
+type Fingerprint map[string]fileState
+
+// Stat fingerprints the files behind paths
+func Stat(paths Paths) Fingerprint {
+	fingerprint := Fingerprint{}
+	for _, path := range []string{paths.Default, paths.User, paths.Repo} {
+		if path == "" {
+			continue
+		}
+		var state fileState
+		if info, err := os.Stat(path); err == nil {
+			state = fileState{size: info.Size(), modTime: info.ModTime().UnixNano()}
+		}
+		fingerprint[path] = state
+	}
+	return fingerprint
+}
+
+// ChangedMsg is sent when a configuration file was created, edited or removed
+type ChangedMsg struct {
+	Fingerprint Fingerprint // Pass to the next Watch call
+}
+
+// Watch waits until a tier file differs from last, then reports it
+// Re-arm it with the message's Fingerprint after handling each change
+func Watch(paths Paths, last Fingerprint) tea.Cmd {
+	return func() tea.Msg {
+		for {
+			time.Sleep(PollInterval)
+			if current := Stat(paths); !maps.Equal(current, last) {
+				return ChangedMsg{Fingerprint: current}
+			}
+		}
+	}
+}
+
+// Change describes a key whose effective value or source differs between loads
+type Change struct {
+	Key string
+	Old Entry // Zero when the key was added
+	New Entry // Zero when the key was removed
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old.Value == "":
+		return fmt.Sprintf("%s=%s added (%s)", c.Key, c.New.Value, c.New.Source)
+	case c.New.Value == "":
+		return fmt.Sprintf("%s=%s removed (%s)", c.Key, c.Old.Value, c.Old.Source)
+	case c.Old.Value == c.New.Value:
+		return fmt.Sprintf("%s=%s now set in %s instead of %s", c.Key, c.New.Value, c.New.Source, c.Old.Source)
+	default:
+		return fmt.Sprintf("%s: %s → %s (%s)", c.Key, c.Old.Value, c.New.Value, c.New.Source)
+	}
+}
+
+// Diff lists the keys that changed from previous to c, sorted by key
+func (c *Config) Diff(previous *Config) []Change {
+	var changes []Change
+	for key, entry := range c.Entries {
+		old := previous.Entries[key]
+		if old.Value != entry.Value || old.Source != entry.Source {
+			changes = append(changes, Change{Key: key, Old: old, New: entry})
+		}
+	}
+	for key, old := range previous.Entries {
+		if _, ok := c.Entries[key]; !ok {
+			changes = append(changes, Change{Key: key, Old: old})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// NewProblems returns the invalid values and skipped lines in c that were
+// not already present in previous, so an existing problem does not block
+// every later reload
+func (c *Config) NewProblems(previous *Config) []string {
+	var problems []string
+
+	known := previous.Problems()
+	for key, problem := range c.Problems() {
+		if known[key] == problem {
+			continue
+		}
+		entry := c.Entries[key]
+		problems = append(problems, fmt.Sprintf("%s:%d: %s", entry.Path, entry.Line, problem))
+	}
+
+	// Line numbers shift with edits, so skipped lines are compared by content
+	skipped := map[string]bool{}
+	for _, w := range previous.Warnings {
+		skipped[w.Path+w.Message] = true
+	}
+	for _, w := range c.Warnings {
+		if !skipped[w.Path+w.Message] {
+			problems = append(problems, w.String())
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Tiers lists the editable configuration files from highest to lowest priority
+var Tiers = []Source{SourceRepo, SourceUser, SourceDefault}
+
+// PathFor returns the file backing a tier
+func (p Paths) PathFor(source Source) string {
+	switch source {
+	case SourceRepo:
+		return p.Repo
+	case SourceUser:
+		return p.User
+	case SourceDefault:
+		return p.Default
+	}
+	return ""
+}
+
+// Set writes KEY=value to the file at path, replacing an existing assignment
+// in place so comments and ordering are preserved
+func Set(path, key, value string) error {
+	if err := Validate(key, value); err != nil {
+		return err
+	}
+	return rewrite(path, key, key+"="+value)
+}
+
+// Unset removes every assignment of key from the file at path
+func Unset(path, key string) error {
+	return rewrite(path, key, "")
+}
+
+func rewrite(path, key, replacement string) error {
+	if path == "" {
+		return fmt.Errorf("no configuration file for this tier")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	var output []string
+	replaced := false
+	for _, line := range lines {
+		lineKey, _, found := strings.Cut(line, "=")
+		if found && strings.TrimSpace(lineKey) == key {
+			// Keep the first assignment's position and drop duplicates
+			if !replaced && replacement != "" {
+				output = append(output, replacement)
+			}
+			replaced = true
+			continue
+		}
+		output = append(output, line)
+	}
+	if !replaced && replacement != "" {
+		output = append(output, replacement)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(output, "\n")+"\n"), 0o644)
+}
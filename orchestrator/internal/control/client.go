@@ -0,0 +1,120 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Client is a connection to a running orchestrator session
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+// Dial connects to the socket at path
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &Client{conn: conn, scanner: scanner}, nil
+}
+
+// Send writes a request and waits for its response
+func (c *Client) Send(req Request) (Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		return Response{}, err
+	}
+
+	for c.scanner.Scan() {
+		var resp Response
+		if err := json.Unmarshal(c.scanner.Bytes(), &resp); err != nil {
+			return Response{}, fmt.Errorf("invalid response: %w", err)
+		}
+		// Skip events that arrive before our answer on subscribed connections
+		if resp.Type == "response" {
+			return resp, nil
+		}
+	}
+	if err := c.scanner.Err(); err != nil {
+		return Response{}, err
+	}
+	return Response{}, io.EOF
+}
+
+// Stream copies raw event lines to w until the connection closes
+func (c *Client) Stream(w io.Writer) error {
+	for c.scanner.Scan() {
+		if _, err := fmt.Fprintln(w, c.scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return c.scanner.Err()
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// FindSession picks the socket a client should talk to:
+// $GEMINI_ORCHESTRATOR_SOCKET when set, otherwise the newest live session,
+// preferring one started in the client's working directory
+func FindSession() (string, error) {
+	if path := os.Getenv(SocketEnv); path != "" {
+		return path, nil
+	}
+
+	sockets, err := filepath.Glob(filepath.Join(SessionDir(), "*.sock"))
+	if err != nil || len(sockets) == 0 {
+		return "", fmt.Errorf("no running orchestrator session found in %s", SessionDir())
+	}
+
+	// Newest sessions first
+	sort.Slice(sockets, func(i, j int) bool {
+		a, _ := os.Stat(sockets[i])
+		b, _ := os.Stat(sockets[j])
+		return a != nil && b != nil && a.ModTime().After(b.ModTime())
+	})
+
+	cwd, _ := os.Getwd()
+	var fallback string
+	for _, path := range sockets {
+		client, err := Dial(path)
+		if err != nil {
+			// Stale socket left behind by a session that did not exit cleanly
+			continue
+		}
+		resp, err := client.Send(Request{Type: RequestPing})
+		client.Close()
+		if err != nil || !resp.OK {
+			continue
+		}
+
+		sessionDir := resp.Data["cwd"]
+		if sessionDir != "" && (cwd == sessionDir || strings.HasPrefix(cwd, sessionDir+string(os.PathSeparator))) {
+			return path, nil
+		}
+		if fallback == "" {
+			fallback = path
+		}
+	}
+
+	if fallback == "" {
+		return "", fmt.Errorf("no running orchestrator session found in %s", SessionDir())
+	}
+	return fallback, nil
+}
@@ -0,0 +1,54 @@
+package control
+
+// The control protocol is line-delimited JSON over a Unix domain socket.
+// Every line a client writes is a Request; the server answers each request
+// with exactly one Response line. Connections that subscribe additionally
+// receive Event lines until they disconnect. See the orchestrator README
+// for the full schema.
+
+// Request types
+const (
+	RequestCommand   = "command"   // Run an orchestrator command as if typed, e.g. "/commit"
+	RequestInsert    = "insert"    // Insert text into the input field
+	RequestOpen      = "open"      // Add a file to the session's file context
+	RequestSubscribe = "subscribe" // Receive events on this connection
+	RequestPing      = "ping"      // Check the session is alive and get its details
+)
+
+// Event names
+const (
+	EventCommandReceived = "command.received" // A command arrived over the socket
+	EventScriptStarted   = "script.started"   // A zsh command or script was launched
+	EventScriptFinished  = "script.finished"  // A zsh command or script exited
+	EventBatchStarted    = "batch.started"    // A /run batch script started
+	EventBatchFinished   = "batch.finished"   // A /run batch script finished or stopped
+	EventCommitCreated   = "commit.created"   // HEAD moved to a new commit after a script or /commit
+	EventConfigReloaded  = "config.reloaded"  // A .gemini-config file changed and was re-applied
+)
+
+// Request is a single line sent by a client
+type Request struct {
+	ID      string   `json:"id,omitempty"`
+	Type    string   `json:"type"`
+	Command string   `json:"command,omitempty"`
+	Text    string   `json:"text,omitempty"`
+	Path    string   `json:"path,omitempty"`
+	Events  []string `json:"events,omitempty"` // Subscribe filter, empty means all events
+}
+
+// Response answers exactly one Request
+type Response struct {
+	Type   string            `json:"type"` // Always "response"
+	ID     string            `json:"id,omitempty"`
+	OK     bool              `json:"ok"`
+	Queued bool              `json:"queued,omitempty"` // The orchestrator was busy, the request runs when it resumes
+	Error  string            `json:"error,omitempty"`
+	Data   map[string]string `json:"data,omitempty"`
+}
+
+// Event is pushed to subscribed connections
+type Event struct {
+	Type  string            `json:"type"` // Always "event"
+	Event string            `json:"event"`
+	Data  map[string]string `json:"data,omitempty"`
+}
@@ -0,0 +1,264 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SocketEnv is exported to child processes so scripts can talk back to the session
+const SocketEnv = "GEMINI_ORCHESTRATOR_SOCKET"
+
+// How long a client waits for the UI before its request is reported as queued
+const replyTimeout = 2 * time.Second
+
+// Queue lengths. Requests wait in order while the UI is suspended; a
+// subscriber that falls this many messages behind is disconnected rather
+// than slowing down the UI.
+const (
+	requestQueue    = 64
+	subscriberQueue = 256
+)
+
+var errSlowSubscriber = errors.New("subscriber is not reading its events")
+
+// RequestMsg delivers a client request to the Bubble Tea update loop
+type RequestMsg struct {
+	Request Request
+	reply   chan Response
+}
+
+// Reply answers the request; it must be called exactly once per RequestMsg
+func (r RequestMsg) Reply(data map[string]string, err error) {
+	resp := Response{Type: "response", ID: r.Request.ID, OK: err == nil, Data: data}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	r.reply <- resp
+}
+
+// Server listens on the session socket and fans events out to subscribers
+type Server struct {
+	path        string
+	cwd         string
+	listener    net.Listener
+	requests    chan RequestMsg
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber is one client connection. Responses and events are queued and
+// written by the connection's own goroutine, so a slow client never blocks
+// Publish or the UI.
+type subscriber struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	events map[string]bool
+	out    chan []byte // Closed by serve once the client is gone
+}
+
+// SessionDir returns the directory holding the sockets of running sessions
+func SessionDir() string {
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "gemini-orchestrator")
+	}
+	return filepath.Join(os.TempDir(), "gemini-orchestrator-"+strconv.Itoa(os.Getuid()))
+}
+
+// SessionPath returns the socket path for this process
+func SessionPath() string {
+	return filepath.Join(SessionDir(), strconv.Itoa(os.Getpid())+".sock")
+}
+
+// Start listens on path and accepts clients in the background
+func Start(path string) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	// A leftover socket from a crashed session with the same PID
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	cwd, _ := os.Getwd()
+	s := &Server{
+		path:        path,
+		cwd:         cwd,
+		listener:    listener,
+		requests:    make(chan RequestMsg, requestQueue),
+		subscribers: map[*subscriber]struct{}{},
+	}
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Path returns the socket path clients connect to
+func (s *Server) Path() string {
+	return s.path
+}
+
+// Listen waits for the next client request
+// Like signal listening, it must be re-armed after every RequestMsg
+func (s *Server) Listen() tea.Cmd {
+	return func() tea.Msg {
+		return <-s.requests
+	}
+}
+
+// Publish sends an event to every subscriber interested in it
+func (s *Server) Publish(event string, data map[string]string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if !sub.wants(event) {
+			continue
+		}
+		if err := sub.write(Event{Type: "event", Event: event, Data: data}); err != nil {
+			// Closing the connection ends serve, which stops the writer
+			delete(s.subscribers, sub)
+			sub.conn.Close()
+		}
+	}
+}
+
+// Close stops listening and removes the socket file
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	sub := &subscriber{conn: conn, out: make(chan []byte, subscriberQueue)}
+	go sub.writeLoop()
+	defer func() {
+		// Once removed nothing else writes to sub, so out can be closed;
+		// the writer flushes what is queued and closes the connection
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+		close(sub.out)
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			sub.write(Response{Type: "response", Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+
+		switch req.Type {
+		case RequestSubscribe:
+			sub.mu.Lock()
+			sub.events = map[string]bool{}
+			for _, event := range req.Events {
+				sub.events[event] = true
+			}
+			sub.mu.Unlock()
+
+			s.mu.Lock()
+			s.subscribers[sub] = struct{}{}
+			s.mu.Unlock()
+			sub.write(Response{Type: "response", ID: req.ID, OK: true})
+		case RequestPing:
+			// Answered without the UI so discovery works while a script runs
+			sub.write(Response{Type: "response", ID: req.ID, OK: true, Data: map[string]string{
+				"pid": strconv.Itoa(os.Getpid()),
+				"cwd": s.cwd,
+			}})
+		case RequestCommand, RequestInsert, RequestOpen:
+			sub.write(s.dispatch(req))
+		default:
+			sub.write(Response{Type: "response", ID: req.ID, Error: fmt.Sprintf("unknown request type %q", req.Type)})
+		}
+	}
+}
+
+// dispatch hands the request to the UI and waits for its answer
+// While a script runs in the foreground the UI is suspended, so the
+// request is reported as queued rather than blocking the client; queued
+// requests reach the UI in the order they arrived
+func (s *Server) dispatch(req Request) Response {
+	msg := RequestMsg{Request: req, reply: make(chan Response, 1)}
+
+	select {
+	case s.requests <- msg:
+	default:
+		return Response{Type: "response", ID: req.ID, Error: fmt.Sprintf("session is busy, %d requests are already queued", requestQueue)}
+	}
+
+	select {
+	case resp := <-msg.reply:
+		return resp
+	case <-time.After(replyTimeout):
+		return Response{Type: "response", ID: req.ID, OK: true, Queued: true}
+	}
+}
+
+func (sub *subscriber) wants(event string) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return len(sub.events) == 0 || sub.events[event]
+}
+
+// write queues a message for the connection without blocking
+func (sub *subscriber) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case sub.out <- append(data, '\n'):
+		return nil
+	default:
+		return errSlowSubscriber
+	}
+}
+
+// writeLoop writes queued messages until the queue is closed or a write
+// fails, then closes the connection
+func (sub *subscriber) writeLoop() {
+	defer sub.conn.Close()
+	for data := range sub.out {
+		sub.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err := sub.conn.Write(data); err != nil {
+			return
+		}
+	}
+}
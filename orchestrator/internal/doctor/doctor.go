@@ -0,0 +1,300 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gemini-orchestrator/internal/config"
+)
+
+// Status is the outcome of a single check
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) Icon() string {
+	switch s {
+	case Pass:
+		return "✅"
+	case Warn:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
+
+// Result describes one verified dependency or setting
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Hint   string // How to fix a warning or failure
+}
+
+// Summary counts results by status
+func Summary(results []Result) (passed, warnings, failures int) {
+	for _, r := range results {
+		switch r.Status {
+		case Pass:
+			passed++
+		case Warn:
+			warnings++
+		default:
+			failures++
+		}
+	}
+	return passed, warnings, failures
+}
+
+// Timeout for each external command, so a hanging tool cannot stall the report
+const commandTimeout = 5 * time.Second
+
+// tool describes an executable the orchestrator or its scripts shell out to
+type tool struct {
+	name     string
+	args     []string // Arguments that print the version
+	required bool     // Missing optional tools only warn
+	hint     string
+}
+
+var tools = []tool{
+	{name: "zsh", args: []string{"--version"}, required: true, hint: "Install zsh with your package manager"},
+	{name: "git", args: []string{"--version"}, required: true, hint: "Install git from https://git-scm.com"},
+	{name: "gh", args: []string{"--version"}, required: true, hint: "Install the GitHub CLI from https://cli.github.com"},
+	{name: "gemini", args: []string{"--version"}, required: true, hint: "Install the Gemini CLI: npm install -g @google/gemini-cli"},
+	{name: "gum", args: []string{"--version"}, required: true, hint: "Install gum from https://github.com/charmbracelet/gum"},
+	{name: "jq", args: []string{"--version"}, hint: "Install jq; auto-commit and auto-pr use it to detect existing PRs"},
+	{name: "python3", args: []string{"--version"}, hint: "Install python3; auto-pr and auto-issue use it to parse gh commands"},
+}
+
+var scripts = []string{"auto-commit", "auto-pr", "auto-issue"}
+
+// Run performs every check and returns the results in display order
+func Run() []Result {
+	var results []Result
+
+	for _, t := range tools {
+		results = append(results, checkTool(t))
+	}
+	results = append(results, checkGhAuth())
+	results = append(results, checkScripts()...)
+	results = append(results, checkConfigFiles()...)
+	results = append(results, checkGeminiContext())
+
+	return results
+}
+
+func checkTool(t tool) Result {
+	result := Result{Name: t.name}
+
+	path, err := exec.LookPath(t.name)
+	if err != nil {
+		result.Status = Warn
+		if t.required {
+			result.Status = Fail
+		}
+		result.Detail = "not found on PATH"
+		result.Hint = t.hint
+		return result
+	}
+
+	output, err := run(t.name, t.args...)
+	if err != nil {
+		result.Status = Warn
+		result.Detail = fmt.Sprintf("%s failed to report its version: %v", path, err)
+		return result
+	}
+
+	result.Detail = firstLine(output)
+	return result
+}
+
+func checkGhAuth() Result {
+	result := Result{Name: "gh auth"}
+
+	if _, err := exec.LookPath("gh"); err != nil {
+		result.Status = Fail
+		result.Detail = "gh is not installed"
+		result.Hint = "Install gh, then run: gh auth login"
+		return result
+	}
+
+	// gh prints its status report on stderr
+	output, err := run("gh", "auth", "status")
+	if err != nil {
+		result.Status = Fail
+		result.Detail = "not logged in to GitHub"
+		if line := firstLine(output); line != "" {
+			result.Detail = line
+		}
+		result.Hint = "Run: gh auth login"
+		return result
+	}
+
+	result.Detail = "logged in"
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Logged in to") {
+			result.Detail = strings.TrimLeft(strings.TrimSpace(line), "✓ ")
+			break
+		}
+	}
+	return result
+}
+
+// checkScripts verifies the auto-* commands resolve to a complete checkout,
+// and to the same one the orchestrator was built from
+func checkScripts() []Result {
+	installed := installedRepository()
+
+	var results []Result
+	for _, name := range scripts {
+		result := Result{Name: name}
+
+		path, err := exec.LookPath(name)
+		if err != nil {
+			result.Status = Fail
+			result.Detail = "not found on PATH"
+			result.Hint = "Run install.zsh from the gemini-cli-scripts repository"
+			results = append(results, result)
+			continue
+		}
+
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			result.Status = Fail
+			result.Detail = fmt.Sprintf("%s is a broken symlink", path)
+			result.Hint = "Re-run install.zsh to recreate the symlinks"
+			results = append(results, result)
+			continue
+		}
+
+		repo := filepath.Dir(resolved)
+		result.Detail = resolved
+		switch {
+		case !fileExists(filepath.Join(repo, "config", "config_loader.zsh")):
+			result.Status = Fail
+			result.Detail = fmt.Sprintf("%s is not inside a gemini-cli-scripts checkout", resolved)
+			result.Hint = "Re-run install.zsh from the repository"
+		case installed != "" && repo != installed:
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("%s does not belong to %s, which this orchestrator was built from", resolved, installed)
+			result.Hint = fmt.Sprintf("Re-run %s to point the scripts at this checkout", filepath.Join(installed, "install.zsh"))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkConfigFiles reports lines load_config_file silently skips
+func checkConfigFiles() []Result {
+	c, err := config.Load(config.DefaultPaths())
+	if err != nil {
+		return []Result{{Name: "config", Status: Fail, Detail: err.Error(), Hint: "Check the file permissions of your .gemini-config files"}}
+	}
+
+	var results []Result
+	for _, path := range []string{c.Paths.Default, c.Paths.User, c.Paths.Repo} {
+		if path == "" || !fileExists(path) {
+			continue
+		}
+
+		var problems []string
+		for _, w := range c.Warnings {
+			if w.Path == path {
+				problems = append(problems, fmt.Sprintf("line %d: %s", w.Line, w.Message))
+			}
+		}
+
+		result := Result{Name: "config", Detail: path + " parsed"}
+		if len(problems) > 0 {
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("%s: %s", path, strings.Join(problems, "; "))
+			result.Hint = "Use KEY=value lines; invalid lines are ignored by the scripts"
+		}
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		results = append(results, Result{Name: "config", Detail: "no .gemini-config files, using defaults"})
+	}
+	return results
+}
+
+// checkGeminiContext mirrors load_gemini_context: GEMINI.md in the current
+// directory or the git root, ignored when larger than 2KB
+func checkGeminiContext() Result {
+	result := Result{Name: "GEMINI.md"}
+
+	var candidates []string
+	if cwd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(cwd, "GEMINI.md"))
+	}
+	if root, err := run("git", "rev-parse", "--show-toplevel"); err == nil {
+		candidates = append(candidates, filepath.Join(strings.TrimSpace(root), "GEMINI.md"))
+	}
+
+	for _, path := range candidates {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.Size() > 2048 {
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("%s is %d bytes and will be ignored", path, info.Size())
+			result.Hint = "Keep GEMINI.md under 2KB so the scripts send it to Gemini"
+			return result
+		}
+		result.Detail = path
+		return result
+	}
+
+	result.Status = Warn
+	result.Detail = "not found in the current directory or git root"
+	result.Hint = "Add a GEMINI.md describing the project to improve generated content"
+	return result
+}
+
+// installedRepository returns the checkout the running binary was built in,
+// or "" when it cannot be determined (e.g. "go run")
+func installedRepository() string {
+	execPath, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+
+	repo := filepath.Dir(filepath.Dir(execPath))
+	if !fileExists(filepath.Join(repo, "config", "config_loader.zsh")) {
+		return ""
+	}
+	return repo
+}
+
+func run(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
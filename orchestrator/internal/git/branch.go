@@ -0,0 +1,259 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Branch is a local branch as git for-each-ref reports it
+type Branch struct {
+	Name     string
+	Current  bool
+	Upstream string // Empty without a tracking branch
+	Gone     bool   // The upstream was deleted
+	Ahead    int
+	Behind   int
+	Hash     string
+	Subject  string
+	Date     time.Time
+}
+
+// Branches lists local branches, most recently committed first
+func Branches(root string) ([]Branch, error) {
+	output, err := run("-C", root, "for-each-ref", "--sort=-committerdate",
+		"--format=%(HEAD)%00%(refname:short)%00%(upstream:short)%00%(upstream:track,nobracket)%00%(objectname:short)%00%(committerdate:unix)%00%(contents:subject)",
+		"refs/heads")
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []Branch
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("unexpected git for-each-ref line %q", line)
+		}
+		branch := Branch{
+			Current:  fields[0] == "*",
+			Name:     fields[1],
+			Upstream: fields[2],
+			Hash:     fields[4],
+			Subject:  fields[6],
+		}
+		if timestamp, err := strconv.ParseInt(fields[5], 10, 64); err == nil {
+			branch.Date = time.Unix(timestamp, 0)
+		}
+		// Tracking is "ahead 2, behind 1", "gone" or empty
+		for _, part := range strings.Split(fields[3], ", ") {
+			switch {
+			case part == "gone":
+				branch.Gone = true
+			case strings.HasPrefix(part, "ahead "):
+				branch.Ahead, _ = strconv.Atoi(strings.TrimPrefix(part, "ahead "))
+			case strings.HasPrefix(part, "behind "):
+				branch.Behind, _ = strconv.Atoi(strings.TrimPrefix(part, "behind "))
+			}
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// ValidateBranchName applies the rules of git check-ref-format --branch,
+// so a name can be checked while it is typed
+func ValidateBranchName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("branch name is empty")
+	case name == "HEAD" || name == "@":
+		return fmt.Errorf("%q is not a valid branch name", name)
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("branch name cannot start with '-'")
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return fmt.Errorf("branch name cannot start or end with '/'")
+	case strings.HasSuffix(name, "."):
+		return fmt.Errorf("branch name cannot end with '.'")
+	case strings.Contains(name, "//"):
+		return fmt.Errorf("branch name cannot contain '//'")
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("branch name cannot contain '..'")
+	case strings.Contains(name, "@{"):
+		return fmt.Errorf("branch name cannot contain '@{'")
+	}
+
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("branch name cannot contain control characters")
+		}
+		if strings.ContainsRune(" ~^:?*[\\", r) {
+			return fmt.Errorf("branch name cannot contain %q", r)
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") {
+			return fmt.Errorf("branch name components cannot start with '.'")
+		}
+		if strings.HasSuffix(component, ".lock") {
+			return fmt.Errorf("branch name components cannot end with '.lock'")
+		}
+	}
+	return nil
+}
+
+// SwitchBranch checks out an existing branch
+func SwitchBranch(root, name string) error {
+	_, err := run("-C", root, "switch", name)
+	return err
+}
+
+// CreateBranch creates a branch at HEAD and switches to it
+func CreateBranch(root, name string) error {
+	if err := ValidateBranchName(name); err != nil {
+		return err
+	}
+	_, err := run("-C", root, "switch", "-c", name)
+	return err
+}
+
+// RenameBranch renames a local branch
+func RenameBranch(root, oldName, newName string) error {
+	if err := ValidateBranchName(newName); err != nil {
+		return err
+	}
+	_, err := run("-C", root, "branch", "-m", oldName, newName)
+	return err
+}
+
+// DeleteBranch deletes a local branch; without force git refuses when
+// it has commits not merged into its upstream or HEAD
+func DeleteBranch(root, name string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := run("-C", root, "branch", flag, name)
+	return err
+}
+
+// UnmergedCommits counts the commits on branch that are neither in HEAD
+// nor in its upstream, the work deleting it would lose
+func UnmergedCommits(root string, branch Branch) (int, error) {
+	args := []string{"-C", root, "rev-list", "--count", branch.Name, "^HEAD"}
+	if branch.Upstream != "" && !branch.Gone {
+		args = append(args, "^"+branch.Upstream)
+	}
+	output, err := run(args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+// BranchExists reports whether a local branch exists
+func BranchExists(root, name string) bool {
+	_, err := run("-C", root, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// issueKey is the git config key linking a branch to its issue; the zsh
+// scripts read the same key
+func issueKey(branch string) string {
+	return "branch." + branch + ".gemini-issue"
+}
+
+// BranchIssue returns the issue number recorded for branch, 0 when none
+func BranchIssue(root, branch string) int {
+	output, err := run("-C", root, "config", "--get", issueKey(branch))
+	if err != nil {
+		return 0
+	}
+	number, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// SetBranchIssue records the issue a branch works on
+func SetBranchIssue(root, branch string, issue int) error {
+	_, err := run("-C", root, "config", issueKey(branch), strconv.Itoa(issue))
+	return err
+}
+
+// UnsetBranchIssue forgets the issue recorded for branch
+func UnsetBranchIssue(root, branch string) error {
+	_, err := run("-C", root, "config", "--unset", issueKey(branch))
+	return err
+}
+
+// BranchRemote returns the remote and remote branch name a local branch
+// tracks, empty when it has no upstream
+func BranchRemote(root, branch string) (remote, name string) {
+	remoteOutput, err := run("-C", root, "config", "--get", "branch."+branch+".remote")
+	if err != nil {
+		return "", ""
+	}
+	mergeOutput, err := run("-C", root, "config", "--get", "branch."+branch+".merge")
+	if err != nil {
+		return "", ""
+	}
+	return strings.TrimSpace(remoteOutput), strings.TrimPrefix(strings.TrimSpace(mergeOutput), "refs/heads/")
+}
+
+// CommitsNotIn counts the commits on branch that are not reachable from
+// rev, e.g. work added after a pull request's head
+func CommitsNotIn(root, branch, rev string) (int, error) {
+	output, err := run("-C", root, "rev-list", "--count", branch, "^"+rev)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+// Pull fast-forwards the current branch from its upstream
+func Pull(root string) error {
+	_, err := runRemote("-C", root, "pull", "--ff-only")
+	return err
+}
+
+// DeleteRemoteBranch deletes a branch on a remote
+func DeleteRemoteBranch(root, remote, name string) error {
+	_, err := runRemote("-C", root, "push", remote, "--delete", name)
+	return err
+}
+
+// DefaultBranch returns the branch pull requests target: the remote's
+// default branch when origin/HEAD is known, otherwise main or master
+func DefaultBranch(root string) (string, error) {
+	if output, err := run("-C", root, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if name := strings.TrimPrefix(strings.TrimSpace(output), "origin/"); name != "" {
+			return name, nil
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if BranchExists(root, name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("neither main nor master exists, and origin has no default branch")
+}
+
+// BaseRef returns the revision to compare a branch with for base: the
+// local branch, or its remote-tracking branch when there is no local one
+func BaseRef(root, base string) string {
+	if BranchExists(root, base) {
+		return base
+	}
+	return "origin/" + base
+}
+
+// Push pushes branch to remote and sets it as the branch's upstream
+func Push(root, remote, branch string) error {
+	_, err := runRemote("-C", root, "push", "--set-upstream", remote, branch)
+	return err
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CommitStaged records the staged changes with message and returns the new
+// commit's SHA. The message is used as written, only surrounding blank
+// lines and trailing spaces are removed.
+func CommitStaged(root, message string) (string, error) {
+	// Hooks such as linters may take longer than plain git commands
+	if _, err := runTimeout(remoteTimeout, []byte(message), "-C", root, "commit", "--cleanup=whitespace", "--file=-"); err != nil {
+		return "", err
+	}
+	output, err := run("-C", root, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// RecentCommits returns the last count non-merge commits as git log
+// --oneline prints them, the history the commit prompt shows Gemini
+func RecentCommits(root string, count int) (string, error) {
+	output, err := run("-C", root, "log", "--oneline", "--no-merges", fmt.Sprintf("-%d", count))
+	if err != nil {
+		// A repository without commits has no history yet
+		if _, headErr := run("-C", root, "rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// githubRemote matches the owner/name part of HTTPS and SSH GitHub URLs
+var githubRemote = regexp.MustCompile(`github\.com[:/]([^/]+/[^/]+?)(\.git)?/?$`)
+
+// RepoSlug returns "owner/name" for a GitHub origin, the origin's URL for
+// other hosts and "" without an origin
+func RepoSlug(root string) string {
+	output, err := run("-C", root, "remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	url := strings.TrimSpace(output)
+	if match := githubRemote.FindStringSubmatch(url); match != nil {
+		return match[1]
+	}
+	return url
+}
+
+// CommitDetails returns the subject and body of each non-merge commit in
+// rev, the commit history auto-pr shows Gemini
+func CommitDetails(root, rev string) (string, error) {
+	output, err := run("-C", root, "log", "--no-merges", "--pretty=format:%h - %s%n%b", rev, "--")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// DiffStat summarizes the changes of rev, e.g. "main...HEAD", as git diff
+// --stat does within width columns
+func DiffStat(root, rev string, width int) (string, error) {
+	output, err := run("-C", root, "diff", "--no-color", fmt.Sprintf("--stat=%d", width), rev, "--")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(output, "\n"), nil
+}
@@ -0,0 +1,278 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LineKind classifies a line of a unified diff hunk
+type LineKind int
+
+const (
+	LineContext   LineKind = iota // " "
+	LineAdded                     // "+"
+	LineRemoved                   // "-"
+	LineNoNewline                 // "\ No newline at end of file", applies to the line before it
+)
+
+// Line is one line of a hunk without its prefix. Numbers are 1-based and
+// zero on the side the line does not exist on.
+type Line struct {
+	Kind      LineKind
+	Text      string
+	OldNumber int
+	NewNumber int
+}
+
+// IsChange reports whether the line is an addition or removal
+func (l Line) IsChange() bool {
+	return l.Kind == LineAdded || l.Kind == LineRemoved
+}
+
+// Hunk is one "@@ -a,b +c,d @@" block
+type Hunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	Section  string // Function context after the second "@@"
+	Lines    []Line
+}
+
+// Header renders the hunk's "@@" line
+func (h Hunk) Header() string {
+	header := fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldCount, h.NewStart, h.NewCount)
+	if h.Section != "" {
+		header += " " + h.Section
+	}
+	return header
+}
+
+// FileDiff is the diff of a single file
+type FileDiff struct {
+	OldPath string   // Empty for new files
+	NewPath string   // Empty for deleted files
+	Header  []string // "diff --git" and extended header lines up to "+++"
+	Hunks   []Hunk
+	Binary  bool
+}
+
+// Path returns the file's current name, or its old name when deleted
+func (f FileDiff) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Stats counts added and removed lines
+func (f FileDiff) Stats() (added, removed int) {
+	for _, h := range f.Hunks {
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case LineAdded:
+				added++
+			case LineRemoved:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+// DiffOptions selects what Diff compares
+type DiffOptions struct {
+	Staged  bool     // Compare the index with HEAD instead of the work tree with the index
+	Revs    []string // Compare the work tree (or index when Staged) with a revision, or two revisions or a range
+	Commit  string   // Show the changes a commit introduced instead, against its first parent
+	Paths   []string // Limit the diff to these paths
+	Context int      // Lines of context, 3 when zero
+}
+
+// Diff runs git diff in root and parses the result
+func Diff(root string, options DiffOptions) ([]FileDiff, error) {
+	output, err := DiffText(root, options)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(output)
+}
+
+// DiffText runs git diff in root and returns its output unparsed
+func DiffText(root string, options DiffOptions) (string, error) {
+	context := options.Context
+	if context == 0 {
+		context = 3
+	}
+
+	command := "diff"
+	if options.Commit != "" {
+		command = "show"
+	}
+	args := []string{"-C", root, "-c", "core.quotepath=false", command, "--no-color", "--no-ext-diff", "-M", fmt.Sprintf("-U%d", context)}
+	if options.Commit != "" {
+		// Merges would otherwise produce a combined diff ParseDiff cannot read
+		args = append(args, "--format=", "--diff-merges=first-parent", options.Commit)
+	}
+	if options.Staged {
+		args = append(args, "--cached")
+	}
+	args = append(args, options.Revs...)
+	args = append(args, "--")
+	args = append(args, options.Paths...)
+
+	return run(args...)
+}
+
+// ParseDiff parses the output of git diff without color
+func ParseDiff(output string) ([]FileDiff, error) {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+	var oldLine, newLine int
+
+	flushHunk := func() {
+		if file != nil && hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if file != nil {
+			files = append(files, *file)
+		}
+		file = nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushFile()
+			file = &FileDiff{Header: []string{line}}
+			file.OldPath, file.NewPath = parseDiffGitLine(line)
+		case file == nil:
+			if line != "" {
+				return nil, fmt.Errorf("unexpected diff line %q", line)
+			}
+		case hunk == nil && !strings.HasPrefix(line, "@@"):
+			file.Header = append(file.Header, line)
+			switch {
+			case strings.HasPrefix(line, "--- "):
+				file.OldPath = diffPath(strings.TrimPrefix(line, "--- "), "a/")
+			case strings.HasPrefix(line, "+++ "):
+				file.NewPath = diffPath(strings.TrimPrefix(line, "+++ "), "b/")
+			case strings.HasPrefix(line, "new file mode"):
+				file.OldPath = ""
+			case strings.HasPrefix(line, "deleted file mode"):
+				file.NewPath = ""
+			case strings.HasPrefix(line, "rename from "):
+				file.OldPath = unquotePath(strings.TrimPrefix(line, "rename from "))
+			case strings.HasPrefix(line, "rename to "):
+				file.NewPath = unquotePath(strings.TrimPrefix(line, "rename to "))
+			case strings.HasPrefix(line, "Binary files "):
+				file.Binary = true
+			}
+		case strings.HasPrefix(line, "@@"):
+			flushHunk()
+			parsed, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			hunk = &parsed
+			oldLine, newLine = hunk.OldStart, hunk.NewStart
+		default:
+			var l Line
+			switch {
+			case strings.HasPrefix(line, "+"):
+				l = Line{Kind: LineAdded, Text: line[1:], NewNumber: newLine}
+				newLine++
+			case strings.HasPrefix(line, "-"):
+				l = Line{Kind: LineRemoved, Text: line[1:], OldNumber: oldLine}
+				oldLine++
+			case strings.HasPrefix(line, `\`):
+				l = Line{Kind: LineNoNewline, Text: line}
+			default:
+				// Context lines start with a space; some tools strip it from empty lines
+				l = Line{Kind: LineContext, Text: strings.TrimPrefix(line, " "), OldNumber: oldLine, NewNumber: newLine}
+				oldLine++
+				newLine++
+			}
+			hunk.Lines = append(hunk.Lines, l)
+		}
+	}
+	flushFile()
+	return files, nil
+}
+
+// parseHunkHeader parses "@@ -a,b +c,d @@ section"; omitted counts are 1
+func parseHunkHeader(line string) (Hunk, error) {
+	var hunk Hunk
+	rest, ok := strings.CutPrefix(line, "@@ ")
+	ranges, section, found := strings.Cut(rest, " @@")
+	if !ok || !found {
+		return hunk, fmt.Errorf("malformed hunk header %q", line)
+	}
+	hunk.Section = strings.TrimSpace(section)
+
+	oldRange, newRange, found := strings.Cut(ranges, " ")
+	if !found || !strings.HasPrefix(oldRange, "-") || !strings.HasPrefix(newRange, "+") {
+		return hunk, fmt.Errorf("malformed hunk header %q", line)
+	}
+	var err error
+	if hunk.OldStart, hunk.OldCount, err = parseRange(oldRange[1:]); err != nil {
+		return hunk, fmt.Errorf("malformed hunk header %q", line)
+	}
+	if hunk.NewStart, hunk.NewCount, err = parseRange(newRange[1:]); err != nil {
+		return hunk, fmt.Errorf("malformed hunk header %q", line)
+	}
+	return hunk, nil
+}
+
+func parseRange(r string) (start, count int, err error) {
+	startText, countText, found := strings.Cut(r, ",")
+	if start, err = strconv.Atoi(startText); err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return start, 1, nil
+	}
+	count, err = strconv.Atoi(countText)
+	return start, count, err
+}
+
+// parseDiffGitLine extracts both paths from "diff --git a/x b/x", used for
+// mode-only and binary changes that have no ---/+++ lines
+func parseDiffGitLine(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if strings.HasPrefix(rest, `"`) {
+		return "", ""
+	}
+	// Without renames both halves are the same length
+	if len(rest)%2 == 1 {
+		half := (len(rest) - 1) / 2
+		oldPath, newPath := rest[:half], rest[half+1:]
+		if strings.HasPrefix(oldPath, "a/") && strings.HasPrefix(newPath, "b/") {
+			return oldPath[2:], newPath[2:]
+		}
+	}
+	return "", ""
+}
+
+func diffPath(path, prefix string) string {
+	if path == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(unquotePath(path), prefix)
+}
+
+// unquotePath decodes the C-style quoting git uses for unusual file names
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
@@ -0,0 +1,420 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const modifiedDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,7 +1,7 @@ package main
+ one
+-two
++TWO
+ three
+ four
+ five
+-six
++SIX
+ seven
+`
+
+func parseOne(t *testing.T, output string) FileDiff {
+	t.Helper()
+	files, err := ParseDiff(output)
+	if err != nil {
+		t.Fatalf("ParseDiff() failed: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	return files[0]
+}
+
+func TestParseDiffLines(t *testing.T) {
+	file := parseOne(t, modifiedDiff)
+
+	expected := []Line{
+		{Kind: LineContext, Text: "one", OldNumber: 1, NewNumber: 1},
+		{Kind: LineRemoved, Text: "two", OldNumber: 2},
+		{Kind: LineAdded, Text: "TWO", NewNumber: 2},
+		{Kind: LineContext, Text: "three", OldNumber: 3, NewNumber: 3},
+		{Kind: LineContext, Text: "four", OldNumber: 4, NewNumber: 4},
+		{Kind: LineContext, Text: "five", OldNumber: 5, NewNumber: 5},
+		{Kind: LineRemoved, Text: "six", OldNumber: 6},
+		{Kind: LineAdded, Text: "SIX", NewNumber: 6},
+		{Kind: LineContext, Text: "seven", OldNumber: 7, NewNumber: 7},
+	}
+	if len(file.Hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(file.Hunks))
+	}
+	hunk := file.Hunks[0]
+	if !reflect.DeepEqual(expected, hunk.Lines) {
+		t.Errorf("expected lines %+v, got %+v", expected, hunk.Lines)
+	}
+	if hunk.Header() != "@@ -1,7 +1,7 @@ package main" {
+		t.Errorf("expected the header to round-trip, got %q", hunk.Header())
+	}
+	if len(file.Header) != 4 {
+		t.Errorf("expected 4 header lines, got %q", file.Header)
+	}
+	if added, removed := file.Stats(); added != 2 || removed != 2 {
+		t.Errorf("expected 2 added and 2 removed lines, got %d and %d", added, removed)
+	}
+}
+
+func TestParseDiffFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		oldPath string
+		newPath string
+		binary  bool
+		hunks   []string // Headers
+	}{
+		{
+			name:    "modified",
+			output:  modifiedDiff,
+			oldPath: "main.go",
+			newPath: "main.go",
+			hunks:   []string{"@@ -1,7 +1,7 @@ package main"},
+		},
+		{
+			name: "new file with a single line and no newline",
+			output: `diff --git a/notes.txt b/notes.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/notes.txt
+@@ -0,0 +1 @@
++only line
+\ No newline at end of file
+`,
+			newPath: "notes.txt",
+			hunks:   []string{"@@ -0,0 +1,1 @@"},
+		},
+		{
+			name: "deleted",
+			output: `diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`,
+			oldPath: "gone.txt",
+			hunks:   []string{"@@ -1,1 +0,0 @@"},
+		},
+		{
+			name: "pure rename",
+			output: `diff --git a/old.go b/renamed.go
+similarity index 100%
+rename from old.go
+rename to renamed.go
+`,
+			oldPath: "old.go",
+			newPath: "renamed.go",
+		},
+		{
+			name: "rename with quoted paths",
+			output: `diff --git "a/sp ace\"q.txt" "b/caf\303\251.txt"
+similarity index 80%
+rename from "sp ace\"q.txt"
+rename to "caf\303\251.txt"
+index 5555555..6666666 100644
+--- "a/sp ace\"q.txt"
++++ "b/caf\303\251.txt"
+@@ -1,2 +1,2 @@
+ keep
+-old
++new
+`,
+			oldPath: `sp ace"q.txt`,
+			newPath: "café.txt",
+			hunks:   []string{"@@ -1,2 +1,2 @@"},
+		},
+		{
+			name: "mode change only",
+			output: `diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`,
+			oldPath: "run.sh",
+			newPath: "run.sh",
+		},
+		{
+			name: "binary",
+			output: `diff --git a/logo.png b/logo.png
+index 7777777..8888888 100644
+Binary files a/logo.png and b/logo.png differ
+`,
+			oldPath: "logo.png",
+			newPath: "logo.png",
+			binary:  true,
+		},
+	}
+
+	for _, test := range tests {
+		files, err := ParseDiff(test.output)
+		if err != nil {
+			t.Errorf("%s: ParseDiff() failed: %v", test.name, err)
+			continue
+		}
+		if len(files) != 1 {
+			t.Errorf("%s: expected 1 file, got %d", test.name, len(files))
+			continue
+		}
+		file := files[0]
+		if file.OldPath != test.oldPath || file.NewPath != test.newPath {
+			t.Errorf("%s: expected paths %q → %q, got %q → %q", test.name, test.oldPath, test.newPath, file.OldPath, file.NewPath)
+		}
+		if file.Binary != test.binary {
+			t.Errorf("%s: expected binary %v, got %v", test.name, test.binary, file.Binary)
+		}
+		var headers []string
+		for _, hunk := range file.Hunks {
+			headers = append(headers, hunk.Header())
+		}
+		if !reflect.DeepEqual(test.hunks, headers) {
+			t.Errorf("%s: expected hunks %q, got %q", test.name, test.hunks, headers)
+		}
+	}
+
+	// All of them in one diff
+	var combined strings.Builder
+	for _, test := range tests {
+		combined.WriteString(test.output)
+	}
+	files, err := ParseDiff(combined.String())
+	if err != nil {
+		t.Fatalf("ParseDiff() of the combined diff failed: %v", err)
+	}
+	if len(files) != len(tests) {
+		t.Errorf("expected %d files in the combined diff, got %d", len(tests), len(files))
+	}
+}
+
+func TestParseDiffNoNewline(t *testing.T) {
+	file := parseOne(t, `diff --git a/VERSION b/VERSION
+index 4444444..5555555 100644
+--- a/VERSION
++++ b/VERSION
+@@ -1 +1 @@
+-1.0
+\ No newline at end of file
++1.1
+\ No newline at end of file
+`)
+	expected := []Line{
+		{Kind: LineRemoved, Text: "1.0", OldNumber: 1},
+		{Kind: LineNoNewline, Text: `\ No newline at end of file`},
+		{Kind: LineAdded, Text: "1.1", NewNumber: 1},
+		{Kind: LineNoNewline, Text: `\ No newline at end of file`},
+	}
+	if !reflect.DeepEqual(expected, file.Hunks[0].Lines) {
+		t.Errorf("expected lines %+v, got %+v", expected, file.Hunks[0].Lines)
+	}
+	if added, removed := file.Stats(); added != 1 || removed != 1 {
+		t.Errorf("markers must not count as changes, got %d added and %d removed", added, removed)
+	}
+	if pieces := file.Hunks[0].Split(); len(pieces) != 1 {
+		t.Errorf("markers must not split a hunk, got %d pieces", len(pieces))
+	}
+}
+
+func TestParseDiffErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"text before the first file", "hello\ndiff --git a/x b/x\n"},
+		{"hunk header without closing @@", "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -1 +1\n"},
+		{"hunk header with a bad number", "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -a,1 +1 @@\n"},
+		{"hunk header without ranges", "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ @@\n"},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseDiff(test.output); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	if files, err := ParseDiff(""); err != nil || len(files) != 0 {
+		t.Errorf("empty diff: expected no files and no error, got %d files and %v", len(files), err)
+	}
+}
+
+func TestHunkSplit(t *testing.T) {
+	pieces := parseOne(t, modifiedDiff).Hunks[0].Split()
+
+	tests := []struct {
+		header string
+		lines  []string
+	}{
+		// The context between the groups belongs to both pieces
+		{"@@ -1,5 +1,5 @@ package main", []string{"one", "two", "TWO", "three", "four", "five"}},
+		{"@@ -3,5 +3,5 @@ package main", []string{"three", "four", "five", "six", "SIX", "seven"}},
+	}
+	if len(pieces) != len(tests) {
+		t.Fatalf("expected %d pieces, got %d", len(tests), len(pieces))
+	}
+	for i, test := range tests {
+		if pieces[i].Header() != test.header {
+			t.Errorf("piece %d: expected header %q, got %q", i, test.header, pieces[i].Header())
+		}
+		var lines []string
+		for _, l := range pieces[i].Lines {
+			lines = append(lines, l.Text)
+		}
+		if !reflect.DeepEqual(test.lines, lines) {
+			t.Errorf("piece %d: expected lines %q, got %q", i, test.lines, lines)
+		}
+	}
+
+	// A single group, and a single line, are left alone
+	single := parseOne(t, "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -1 +1 @@\n-a\n+b\n").Hunks[0]
+	if pieces := single.Split(); len(pieces) != 1 || !reflect.DeepEqual(single, pieces[0]) {
+		t.Errorf("expected a single-line hunk to stay whole, got %+v", pieces)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	file := parseOne(t, modifiedDiff)
+	hunk := file.Hunks[0]
+	header := strings.Join(file.Header, "\n") + "\n"
+	only := func(indexes ...int) func(int) bool {
+		return func(i int) bool {
+			for _, index := range indexes {
+				if i == index {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	tests := []struct {
+		name     string
+		patch    func() (string, error)
+		expected string
+	}{
+		{
+			name:     "whole hunk",
+			patch:    func() (string, error) { return file.Patch(hunk, nil) },
+			expected: header + "@@ -1,7 +1,7 @@ package main\n one\n-two\n+TWO\n three\n four\n five\n-six\n+SIX\n seven\n",
+		},
+		{
+			// The unselected removal stays as context, the addition is dropped
+			name:     "first group staged",
+			patch:    func() (string, error) { return file.Patch(hunk, only(1, 2)) },
+			expected: header + "@@ -1,7 +1,7 @@ package main\n one\n-two\n+TWO\n three\n four\n five\n six\n seven\n",
+		},
+		{
+			name:     "only a removal staged",
+			patch:    func() (string, error) { return file.Patch(hunk, only(6)) },
+			expected: header + "@@ -1,7 +1,6 @@ package main\n one\n two\n three\n four\n five\n-six\n seven\n",
+		},
+		{
+			// Unstaging keeps unselected additions, which are in the index
+			name:     "second group unstaged",
+			patch:    func() (string, error) { return file.ReversePatch(hunk, only(6, 7)) },
+			expected: header + "@@ -1,7 +1,7 @@ package main\n one\n TWO\n three\n four\n five\n-six\n+SIX\n seven\n",
+		},
+		{
+			name: "no newline markers follow their line",
+			patch: func() (string, error) {
+				f := parseOne(t, "diff --git a/v b/v\n--- a/v\n+++ b/v\n@@ -1 +1 @@\n-1.0\n\\ No newline at end of file\n+1.1\n\\ No newline at end of file\n")
+				return f.Patch(f.Hunks[0], nil)
+			},
+			expected: "diff --git a/v b/v\n--- a/v\n+++ b/v\n@@ -1,1 +1,1 @@\n-1.0\n\\ No newline at end of file\n+1.1\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, test := range tests {
+		patch, err := test.patch()
+		if err != nil {
+			t.Errorf("%s: failed: %v", test.name, err)
+			continue
+		}
+		if patch != test.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expected, patch)
+		}
+	}
+
+	if _, err := file.Patch(hunk, only(0, 3)); err == nil {
+		t.Errorf("a patch of context lines only should fail")
+	}
+	binary := parseOne(t, "diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n")
+	if _, err := binary.Patch(Hunk{}, nil); err == nil {
+		t.Errorf("a patch of a binary file should fail")
+	}
+}
+
+// TestApplyCached stages and unstages single lines in a real repository
+func TestApplyCached(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	gitIn := func(args ...string) string {
+		t.Helper()
+		output, err := run(append([]string{"-C", root, "-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitIn("init", "-q")
+	write("one\ntwo\nthree\nfour\nfive\nsix\nseven\n")
+	gitIn("add", "main.go")
+	gitIn("commit", "-q", "-m", "initial")
+	write("one\nTWO\nthree\nfour\nfive\nSIX\nseven\n")
+
+	// Stage the second piece of the split hunk
+	files, err := Diff(root, DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := files[0].Hunks[0].Split()[1]
+	patch, err := files[0].Patch(second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyCached(root, patch, false); err != nil {
+		t.Fatalf("ApplyCached() failed: %v\n%s", err, patch)
+	}
+	if index := gitIn("show", ":main.go"); index != "one\ntwo\nthree\nfour\nfive\nSIX\nseven\n" {
+		t.Errorf("expected only SIX staged, index has %q", index)
+	}
+
+	// Stage the rest, then unstage just the removal of "two"
+	gitIn("add", "main.go")
+	files, err = Diff(root, DiffOptions{Staged: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err = files[0].ReversePatch(files[0].Hunks[0], func(i int) bool { return i == 1 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyCached(root, patch, true); err != nil {
+		t.Fatalf("ApplyCached(reverse) failed: %v\n%s", err, patch)
+	}
+	if index := gitIn("show", ":main.go"); index != "one\ntwo\nTWO\nthree\nfour\nfive\nSIX\nseven\n" {
+		t.Errorf("expected two back in the index next to TWO, index has %q", index)
+	}
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Timeout for each git invocation, so a locked repository cannot stall the UI
+const commandTimeout = 10 * time.Second
+
+// Timeout for git commands that talk to a remote
+const remoteTimeout = time.Minute
+
+// Root returns the top-level directory of the repository containing the
+// current directory
+func Root() (string, error) {
+	output, err := run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// RepoName returns the base name of the repository root
+func RepoName(root string) string {
+	return filepath.Base(root)
+}
+
+// run executes git and returns its standard output. Errors include git's
+// own message rather than just the exit status.
+func run(args ...string) (string, error) {
+	return runInput(nil, args...)
+}
+
+// runRemote is run with the longer timeout of network operations
+func runRemote(args ...string) (string, error) {
+	return runTimeout(remoteTimeout, nil, args...)
+}
+
+// runInput is run with input fed to git's standard input
+func runInput(input []byte, args ...string) (string, error) {
+	return runTimeout(commandTimeout, input, args...)
+}
+
+func runTimeout(timeout time.Duration, input []byte, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return stdout.String(), fmt.Errorf("git %s: %s", subcommand(args), firstLine(message))
+		}
+		return stdout.String(), fmt.Errorf("git %s: %w", subcommand(args), err)
+	}
+	return stdout.String(), nil
+}
+
+// subcommand names the git command in args for error messages, skipping -C <dir>
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-C" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is one entry of git log
+type Commit struct {
+	Hash      string
+	ShortHash string
+	Parents   []string
+	Author    string
+	Email     string
+	Date      time.Time
+	Subject   string
+	Graph     string   // Graph drawn before the commit, e.g. "| * "
+	Edges     []string // Graph-only lines drawn after the commit, e.g. "|\ "
+}
+
+// ConventionalSubject is a subject in the Conventional Commits format,
+// "type(scope)!: description"
+type ConventionalSubject struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var conventionalSubject = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?: (.*)$`)
+
+// Conventional parses the subject; ok is false when it does not follow
+// the Conventional Commits format
+func (c Commit) Conventional() (subject ConventionalSubject, ok bool) {
+	match := conventionalSubject.FindStringSubmatch(c.Subject)
+	if match == nil {
+		return subject, false
+	}
+	return ConventionalSubject{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Breaking:    match[3] == "!",
+		Description: match[4],
+	}, true
+}
+
+// IsMerge reports whether the commit has more than one parent
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// LogOptions selects the commits Log lists
+type LogOptions struct {
+	Revs  []string // Revisions or ranges, HEAD when empty
+	Paths []string // Only commits touching these paths
+	Limit int      // Maximum number of commits, 500 when zero
+}
+
+// Log lists commits newest first with the graph git log --graph draws
+func Log(root string, options LogOptions) ([]Commit, error) {
+	limit := options.Limit
+	if limit == 0 {
+		limit = 500
+	}
+
+	// Each commit line starts with the graph, then a record separator
+	args := []string{"-C", root, "log", "--graph", "--no-color", fmt.Sprintf("--max-count=%d", limit),
+		"--format=%x1e%H%x1f%h%x1f%P%x1f%an%x1f%ae%x1f%at%x1f%s"}
+	args = append(args, options.Revs...)
+	args = append(args, "--")
+	args = append(args, options.Paths...)
+
+	output, err := run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output)
+}
+
+func parseLog(output string) ([]Commit, error) {
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		graph, record, found := strings.Cut(line, "\x1e")
+		if !found {
+			if len(commits) > 0 {
+				commits[len(commits)-1].Edges = append(commits[len(commits)-1].Edges, strings.TrimRight(line, " "))
+			}
+			continue
+		}
+
+		fields := strings.Split(record, "\x1f")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("unexpected git log line %q", line)
+		}
+		timestamp, err := strconv.ParseInt(fields[5], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log date %q", fields[5])
+		}
+		commits = append(commits, Commit{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Parents:   strings.Fields(fields[2]),
+			Author:    fields[3],
+			Email:     fields[4],
+			Date:      time.Unix(timestamp, 0),
+			Subject:   fields[6],
+			Graph:     graph,
+		})
+	}
+	return commits, nil
+}
+
+// CommitMessage returns the full message of a commit
+func CommitMessage(root, hash string) (string, error) {
+	output, err := run("-C", root, "show", "-s", "--format=%B", hash)
+	return strings.TrimRight(output, "\n"), err
+}
+
+// CommitPatch returns a commit as git show prints it: message, file stats
+// and changes against the first parent
+func CommitPatch(root, hash string) (string, error) {
+	return run("-C", root, "show", "--no-color", "--no-ext-diff", "--stat", "--patch", "--diff-merges=first-parent", hash)
+}
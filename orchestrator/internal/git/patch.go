@@ -0,0 +1,178 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Split breaks a hunk into smaller hunks at the unchanged lines between
+// groups of changes, like "s" in git add -p. Context lines between two
+// groups are shared by both pieces. A hunk with a single group is returned
+// unchanged.
+func (h Hunk) Split() []Hunk {
+	// Find the ranges of consecutive changed lines
+	type group struct{ start, end int } // Lines[start:end]
+	var groups []group
+	for i := 0; i < len(h.Lines); i++ {
+		if !h.Lines[i].IsChange() {
+			continue
+		}
+		start := i
+		for i < len(h.Lines) && (h.Lines[i].IsChange() || h.Lines[i].Kind == LineNoNewline) {
+			i++
+		}
+		groups = append(groups, group{start, i})
+	}
+	if len(groups) < 2 {
+		return []Hunk{h}
+	}
+
+	var pieces []Hunk
+	for i := range groups {
+		// Leading context runs back to the previous group, trailing context
+		// forward to the next one
+		from := 0
+		if i > 0 {
+			from = groups[i-1].end
+		}
+		to := len(h.Lines)
+		if i < len(groups)-1 {
+			to = groups[i+1].start
+		}
+		pieces = append(pieces, newHunk(h.Section, h.Lines[from:to]))
+	}
+	return pieces
+}
+
+// newHunk builds a hunk from lines, deriving its header from their numbers
+func newHunk(section string, lines []Line) Hunk {
+	hunk := Hunk{Section: section, Lines: append([]Line{}, lines...)}
+	first := lines[0]
+
+	for _, l := range lines {
+		switch l.Kind {
+		case LineContext:
+			hunk.OldCount++
+			hunk.NewCount++
+		case LineAdded:
+			hunk.NewCount++
+		case LineRemoved:
+			hunk.OldCount++
+		}
+	}
+
+	// Lines that only exist on one side carry no number for the other
+	for _, l := range lines {
+		if hunk.OldStart == 0 && l.OldNumber != 0 {
+			hunk.OldStart = l.OldNumber
+		}
+		if hunk.NewStart == 0 && l.NewNumber != 0 {
+			hunk.NewStart = l.NewNumber
+		}
+	}
+	if hunk.OldStart == 0 && hunk.OldCount == 0 {
+		hunk.OldStart = max(first.NewNumber-1, 0)
+	}
+	if hunk.NewStart == 0 && hunk.NewCount == 0 {
+		hunk.NewStart = max(first.OldNumber-1, 0)
+	}
+	return hunk
+}
+
+// Patch renders a patch that applies part of hunk to the index. selected
+// reports which lines (by index into hunk.Lines) to apply; nil selects every
+// line. With reverse the patch is meant for git apply -R, to unstage lines
+// from a diff of the index against HEAD.
+//
+// Unselected changes must keep the side the patch is applied to intact:
+// when staging, an unselected removal stays as context and an unselected
+// addition is dropped; when unstaging it is the other way round.
+func (f FileDiff) Patch(hunk Hunk, selected func(int) bool) (string, error) {
+	return f.patch(hunk, selected, false)
+}
+
+// ReversePatch is Patch for unstaging with git apply -R
+func (f FileDiff) ReversePatch(hunk Hunk, selected func(int) bool) (string, error) {
+	return f.patch(hunk, selected, true)
+}
+
+func (f FileDiff) patch(hunk Hunk, selected func(int) bool, reverse bool) (string, error) {
+	if f.Binary {
+		return "", fmt.Errorf("%s is a binary file", f.Path())
+	}
+
+	var lines []Line
+	kept := false // Whether the previous line survived, for "\ No newline" markers
+	changes := 0
+	for i, l := range hunk.Lines {
+		include := selected == nil || selected(i)
+		switch {
+		case l.Kind == LineNoNewline:
+			if kept {
+				lines = append(lines, l)
+			}
+			continue
+		case !l.IsChange() || include:
+			if l.IsChange() {
+				changes++
+			}
+			lines = append(lines, l)
+			kept = true
+		case (l.Kind == LineRemoved) != reverse:
+			// Present on the side being patched, so it must stay as context
+			l.Kind = LineContext
+			lines = append(lines, l)
+			kept = true
+		default:
+			kept = false
+		}
+	}
+	if changes == 0 {
+		return "", fmt.Errorf("no changed lines selected")
+	}
+
+	// The side being patched is unchanged, so its position is the original one
+	rebuilt := newHunk(hunk.Section, lines)
+	if reverse {
+		rebuilt.NewStart, rebuilt.OldStart = hunk.NewStart, hunk.NewStart
+		if rebuilt.NewCount == 0 {
+			rebuilt.OldStart++
+		}
+	} else {
+		rebuilt.OldStart, rebuilt.NewStart = hunk.OldStart, hunk.OldStart
+		if rebuilt.OldCount == 0 {
+			rebuilt.NewStart++
+		}
+	}
+
+	var patch strings.Builder
+	for _, line := range f.Header {
+		patch.WriteString(line + "\n")
+	}
+	patch.WriteString(rebuilt.Header() + "\n")
+	for _, l := range lines {
+		switch l.Kind {
+		case LineContext:
+			patch.WriteString(" " + l.Text + "\n")
+		case LineAdded:
+			patch.WriteString("+" + l.Text + "\n")
+		case LineRemoved:
+			patch.WriteString("-" + l.Text + "\n")
+		case LineNoNewline:
+			patch.WriteString(l.Text + "\n")
+		}
+	}
+	return patch.String(), nil
+}
+
+// ApplyCached applies patch to the index only, reversed to unstage
+// --recount tolerates the hunk line counts of partially selected hunks
+func ApplyCached(root, patch string, reverse bool) error {
+	args := []string{"-C", root, "apply", "--cached", "--recount", "--whitespace=nowarn"}
+	if reverse {
+		args = append(args, "-R")
+	}
+	args = append(args, "-")
+	_, err := runInput([]byte(patch), args...)
+	return err
+}
@@ -0,0 +1,23 @@
+package git
+
+// Stage adds paths (files or directories, relative to the repository root)
+// to the index, including deletions
+func Stage(root string, paths ...string) error {
+	args := append([]string{"-C", root, "add", "-A", "--"}, paths...)
+	_, err := run(args...)
+	return err
+}
+
+// Unstage removes paths from the index, keeping the work tree untouched
+func Unstage(root string, paths ...string) error {
+	// Before the first commit there is no HEAD to restore the index from
+	if _, err := run("-C", root, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		args := append([]string{"-C", root, "rm", "--cached", "-r", "--quiet", "--"}, paths...)
+		_, err := run(args...)
+		return err
+	}
+
+	args := append([]string{"-C", root, "restore", "--staged", "--"}, paths...)
+	_, err := run(args...)
+	return err
+}
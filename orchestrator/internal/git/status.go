@@ -0,0 +1,211 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Kind classifies an entry of git status --porcelain=v2
+type Kind int
+
+const (
+	Changed   Kind = iota // "1": ordinary change
+	Renamed               // "2": rename or copy
+	Unmerged              // "u": merge conflict
+	Untracked             // "?"
+	Ignored               // "!"
+)
+
+// File is one path reported by git status. Index and Worktree hold the X and
+// Y status letters, with '.' meaning unmodified.
+type File struct {
+	Path     string
+	OrigPath string // Source of a rename or copy
+	Kind     Kind
+	Index    byte
+	Worktree byte
+}
+
+// Staged reports whether the file has changes in the index
+func (f File) Staged() bool {
+	return f.Kind != Untracked && f.Kind != Ignored && f.Kind != Unmerged && f.Index != '.'
+}
+
+// Unstaged reports whether the work tree differs from the index
+func (f File) Unstaged() bool {
+	return f.Kind == Unmerged || (f.Kind != Untracked && f.Kind != Ignored && f.Worktree != '.')
+}
+
+// Status is the parsed output of git status --porcelain=v2 --branch
+type Status struct {
+	Root        string // Repository top-level directory
+	Branch      string // Empty when HEAD is detached
+	Head        string // Commit SHA, empty before the first commit
+	Upstream    string // Empty without an upstream
+	Ahead       int
+	Behind      int
+	HasUpstream bool // Ahead and Behind are only meaningful with an upstream
+	Files       []File
+}
+
+// Detached reports whether HEAD is not on a branch
+func (s *Status) Detached() bool {
+	return s.Branch == ""
+}
+
+// Staged returns the files with changes in the index
+func (s *Status) Staged() []File {
+	return s.filter(File.Staged)
+}
+
+// Unstaged returns tracked files whose work tree differs from the index,
+// including unresolved conflicts
+func (s *Status) Unstaged() []File {
+	return s.filter(File.Unstaged)
+}
+
+// Untracked returns files git does not track yet
+func (s *Status) Untracked() []File {
+	return s.filter(func(f File) bool { return f.Kind == Untracked })
+}
+
+// Counts returns the number of staged, unstaged and untracked files
+func (s *Status) Counts() (staged, unstaged, untracked int) {
+	return len(s.Staged()), len(s.Unstaged()), len(s.Untracked())
+}
+
+// Clean reports whether there is nothing to commit
+func (s *Status) Clean() bool {
+	return len(s.Files) == 0
+}
+
+func (s *Status) filter(keep func(File) bool) []File {
+	var files []File
+	for _, f := range s.Files {
+		if keep(f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// LoadStatus reads the status of the repository containing the current directory
+func LoadStatus() (*Status, error) {
+	root, err := Root()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := run("status", "--porcelain=v2", "--branch", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := ParseStatus(output)
+	if err != nil {
+		return nil, err
+	}
+	status.Root = root
+	return status, nil
+}
+
+// ParseStatus parses git status --porcelain=v2 --branch -z output
+func ParseStatus(output string) (*Status, error) {
+	status := &Status{}
+
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+
+		switch record[0] {
+		case '#':
+			parseHeader(record, status)
+		case '1':
+			// 1 XY sub mH mI mW hH hI path
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("malformed status entry %q", record)
+			}
+			status.Files = append(status.Files, newFile(Changed, fields[1], fields[8]))
+		case '2':
+			// 2 XY sub mH mI mW hH hI Xscore path, followed by the original path
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || i+1 >= len(records) {
+				return nil, fmt.Errorf("malformed rename entry %q", record)
+			}
+			file := newFile(Renamed, fields[1], fields[9])
+			i++
+			file.OrigPath = records[i]
+			status.Files = append(status.Files, file)
+		case 'u':
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("malformed conflict entry %q", record)
+			}
+			status.Files = append(status.Files, newFile(Unmerged, fields[1], fields[10]))
+		case '?':
+			status.Files = append(status.Files, File{Path: record[2:], Kind: Untracked, Index: '?', Worktree: '?'})
+		case '!':
+			status.Files = append(status.Files, File{Path: record[2:], Kind: Ignored, Index: '!', Worktree: '!'})
+		default:
+			return nil, fmt.Errorf("unknown status entry %q", record)
+		}
+	}
+	return status, nil
+}
+
+func newFile(kind Kind, xy, path string) File {
+	file := File{Path: path, Kind: kind, Index: '.', Worktree: '.'}
+	if len(xy) == 2 {
+		file.Index, file.Worktree = xy[0], xy[1]
+	}
+	return file
+}
+
+func parseHeader(record string, status *Status) {
+	fields := strings.Fields(record)
+	if len(fields) < 3 {
+		return
+	}
+
+	switch fields[1] {
+	case "branch.oid":
+		if fields[2] != "(initial)" {
+			status.Head = fields[2]
+		}
+	case "branch.head":
+		if fields[2] != "(detached)" {
+			status.Branch = fields[2]
+		}
+	case "branch.upstream":
+		status.Upstream = fields[2]
+	case "branch.ab":
+		if len(fields) == 4 {
+			status.HasUpstream = true
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		}
+	}
+}
+
+// StatusMsg carries a refreshed repository status; Err is set outside a
+// repository or when git fails
+type StatusMsg struct {
+	Status *Status
+	Err    error
+}
+
+// RefreshStatus loads the status in the background
+func RefreshStatus() tea.Cmd {
+	return func() tea.Msg {
+		status, err := LoadStatus()
+		return StatusMsg{Status: status, Err: err}
+	}
+}
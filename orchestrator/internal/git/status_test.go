@@ -0,0 +1,157 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// status joins porcelain v2 records the way -z separates them
+func status(records ...string) string {
+	return strings.Join(records, "\x00") + "\x00"
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected Status
+	}{
+		{
+			name:     "empty output",
+			output:   "",
+			expected: Status{},
+		},
+		{
+			name: "branch with upstream",
+			output: status("# branch.oid 0123456789abcdef0123456789abcdef01234567", "# branch.head feat/login",
+				"# branch.upstream origin/feat/login", "# branch.ab +2 -1"),
+			expected: Status{Branch: "feat/login", Head: "0123456789abcdef0123456789abcdef01234567",
+				Upstream: "origin/feat/login", Ahead: 2, Behind: 1, HasUpstream: true},
+		},
+		{
+			name:     "initial commit on a branch without upstream",
+			output:   status("# branch.oid (initial)", "# branch.head main"),
+			expected: Status{Branch: "main"},
+		},
+		{
+			name:     "detached head",
+			output:   status("# branch.oid 0123456", "# branch.head (detached)"),
+			expected: Status{Head: "0123456"},
+		},
+		{
+			name: "ordinary changes",
+			output: status("1 M. N... 100644 100644 100644 1111111 2222222 staged.go",
+				"1 .M N... 100644 100644 100644 1111111 1111111 unstaged.go",
+				"1 MD N... 100644 100644 000000 1111111 2222222 both.go"),
+			expected: Status{Files: []File{
+				{Path: "staged.go", Kind: Changed, Index: 'M', Worktree: '.'},
+				{Path: "unstaged.go", Kind: Changed, Index: '.', Worktree: 'M'},
+				{Path: "both.go", Kind: Changed, Index: 'M', Worktree: 'D'},
+			}},
+		},
+		{
+			name: "paths are taken verbatim, spaces and quotes included",
+			output: status(`1 A. N... 000000 100644 100644 0000000 2222222 docs/read me "first".md`,
+				"? café/naïve file.txt"),
+			expected: Status{Files: []File{
+				{Path: `docs/read me "first".md`, Kind: Changed, Index: 'A', Worktree: '.'},
+				{Path: "café/naïve file.txt", Kind: Untracked, Index: '?', Worktree: '?'},
+			}},
+		},
+		{
+			name: "renames and copies carry the original path in the next record",
+			output: status("2 R. N... 100644 100644 100644 1111111 1111111 R100 new name.go", "old name.go",
+				"2 C. N... 100644 100644 100644 1111111 1111111 C75 copy.go", "source.go",
+				"1 .M N... 100644 100644 100644 1111111 1111111 after.go"),
+			expected: Status{Files: []File{
+				{Path: "new name.go", OrigPath: "old name.go", Kind: Renamed, Index: 'R', Worktree: '.'},
+				{Path: "copy.go", OrigPath: "source.go", Kind: Renamed, Index: 'C', Worktree: '.'},
+				{Path: "after.go", Kind: Changed, Index: '.', Worktree: 'M'},
+			}},
+		},
+		{
+			name: "untracked, ignored and unmerged entries",
+			output: status("u UU N... 100644 100644 100644 100644 1111111 2222222 3333333 conflict.go",
+				"u AA N... 000000 100644 100644 100644 0000000 2222222 3333333 both added.go",
+				"? new.txt", "! build/"),
+			expected: Status{Files: []File{
+				{Path: "conflict.go", Kind: Unmerged, Index: 'U', Worktree: 'U'},
+				{Path: "both added.go", Kind: Unmerged, Index: 'A', Worktree: 'A'},
+				{Path: "new.txt", Kind: Untracked, Index: '?', Worktree: '?'},
+				{Path: "build/", Kind: Ignored, Index: '!', Worktree: '!'},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseStatus(test.output)
+		if err != nil {
+			t.Errorf("%s: ParseStatus() failed: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(&test.expected, parsed) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, *parsed)
+		}
+	}
+}
+
+func TestParseStatusErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"short ordinary entry", status("1 .M N... 100644 file.go")},
+		{"rename without original path", "2 R. N... 100644 100644 100644 1111111 1111111 R100 new.go"},
+		{"short rename entry", status("2 R. N... new.go", "old.go")},
+		{"short conflict entry", status("u UU N... conflict.go")},
+		{"unknown entry", status("x something")},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseStatus(test.output); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestStatusFilters(t *testing.T) {
+	s, err := ParseStatus(status(
+		"1 M. N... 100644 100644 100644 1111111 2222222 staged.go",
+		"1 .M N... 100644 100644 100644 1111111 1111111 unstaged.go",
+		"1 MM N... 100644 100644 100644 1111111 2222222 both.go",
+		"2 R. N... 100644 100644 100644 1111111 1111111 R100 renamed.go", "orig.go",
+		"u UU N... 100644 100644 100644 100644 1111111 2222222 3333333 conflict.go",
+		"? new.txt",
+		"! build/",
+	))
+	if err != nil {
+		t.Fatalf("ParseStatus() failed: %v", err)
+	}
+
+	paths := func(files []File) []string {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Path)
+		}
+		return names
+	}
+	if expected := []string{"staged.go", "both.go", "renamed.go"}; !reflect.DeepEqual(expected, paths(s.Staged())) {
+		t.Errorf("Staged(): expected %v, got %v", expected, paths(s.Staged()))
+	}
+	if expected := []string{"unstaged.go", "both.go", "conflict.go"}; !reflect.DeepEqual(expected, paths(s.Unstaged())) {
+		t.Errorf("Unstaged(): expected %v, got %v", expected, paths(s.Unstaged()))
+	}
+	if expected := []string{"new.txt"}; !reflect.DeepEqual(expected, paths(s.Untracked())) {
+		t.Errorf("Untracked(): expected %v, got %v", expected, paths(s.Untracked()))
+	}
+	if staged, unstaged, untracked := s.Counts(); staged != 3 || unstaged != 3 || untracked != 1 {
+		t.Errorf("Counts(): expected 3, 3, 1, got %d, %d, %d", staged, unstaged, untracked)
+	}
+	if s.Clean() {
+		t.Errorf("Clean() should be false with changes")
+	}
+	if !s.Detached() {
+		t.Errorf("Detached() should be true without a branch.head header")
+	}
+}
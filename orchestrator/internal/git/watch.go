@@ -0,0 +1,138 @@
+package git
+
+import (
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Polling intervals. Stat-ing a few files in .git is cheap and done every
+// second; the work tree is compared through git status, which uses the
+// index's stat cache and skips ignored directories, on a slower timer that
+// backs off further when status is slow in a large repository.
+const (
+	watchInterval  = time.Second
+	statusInterval = 5 * time.Second
+	debounceWindow = 300 * time.Millisecond
+	statusBackoff  = 10 // Wait at least this many status durations between runs
+)
+
+// Watcher polls the repository metadata (HEAD, the index and refs) and the
+// output of git status for changes made outside the orchestrator. Polling
+// needs no extra dependencies and behaves the same on every platform and
+// filesystem.
+type Watcher struct {
+	root     string
+	gitDir   string
+	metadata uint64 // Hash of HEAD, the index and refs
+	status   uint64 // Hash of git status
+	next     time.Time
+	cost     time.Duration // How long git status last took
+}
+
+// ChangedMsg reports that the repository changed since the last message
+type ChangedMsg struct{}
+
+// NewWatcher watches the repository containing the current directory
+func NewWatcher() (*Watcher, error) {
+	root, err := Root()
+	if err != nil {
+		return nil, err
+	}
+	gitDir, err := run("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{root: root, gitDir: strings.TrimSpace(gitDir)}
+	w.metadata = w.hashMetadata()
+	w.status = w.hashStatus()
+	return w, nil
+}
+
+// Watch blocks until the repository changes and has been quiet for the
+// debounce window, so a checkout touching many files produces a single
+// message. Re-arm it after handling each ChangedMsg.
+func (w *Watcher) Watch() tea.Cmd {
+	return func() tea.Msg {
+		for {
+			time.Sleep(watchInterval)
+
+			changed := false
+			if current := w.hashMetadata(); current != w.metadata {
+				// Wait until two consecutive looks agree
+				for {
+					time.Sleep(debounceWindow)
+					next := w.hashMetadata()
+					if next == current {
+						break
+					}
+					current = next
+				}
+				w.metadata, changed = current, true
+			}
+
+			// Metadata changes usually change status too; check it now so
+			// the next timed run does not report the same change again
+			if changed || !time.Now().Before(w.next) {
+				if current := w.hashStatus(); current != w.status {
+					w.status, changed = current, true
+				}
+			}
+			if changed {
+				return ChangedMsg{}
+			}
+		}
+	}
+}
+
+// hashMetadata hashes the names, sizes and modification times of HEAD, the
+// index and the refs
+func (w *Watcher) hashMetadata() uint64 {
+	h := fnv.New64a()
+	for _, name := range []string{"HEAD", "index", "packed-refs"} {
+		hashFile(h, filepath.Join(w.gitDir, name), name)
+	}
+	filepath.WalkDir(filepath.Join(w.gitDir, "refs"), func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			hashFile(h, path, path)
+		}
+		return nil
+	})
+	return h.Sum64()
+}
+
+// hashStatus hashes git status and schedules the next run. Optional locks
+// are off so status does not rewrite the index, which would look like a
+// metadata change.
+func (w *Watcher) hashStatus() uint64 {
+	start := time.Now()
+	output, _ := run("-C", w.root, "--no-optional-locks", "status", "--porcelain=v1", "-z", "--untracked-files=normal")
+	w.cost = time.Since(start)
+	w.next = time.Now().Add(max(statusInterval, statusBackoff*w.cost))
+
+	h := fnv.New64a()
+	h.Write([]byte(output))
+	return h.Sum64()
+}
+
+type hasher interface {
+	Write([]byte) (int, error)
+}
+
+// hashFile folds a file's name, size and modification time into h; a
+// missing file still contributes its name so deleting it is noticed
+func hashFile(h hasher, path, name string) {
+	h.Write([]byte(name))
+	if info, err := os.Lstat(path); err == nil {
+		h.Write([]byte(strconv.FormatInt(info.Size(), 10)))
+		h.Write([]byte(strconv.FormatInt(info.ModTime().UnixNano(), 10)))
+	}
+	h.Write([]byte{0})
+}
@@ -0,0 +1,102 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Timeout for each gh invocation; gh talks to the network
+const commandTimeout = 15 * time.Second
+
+// PullRequest is the subset of gh's PR fields the orchestrator shows
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+
+	// Only filled by LatestPullRequest and ViewPullRequest
+	BaseRefName string `json:"baseRefName"`
+	HeadRefOid  string `json:"headRefOid"`
+
+	// Only filled by ViewPullRequest
+	Body    string `json:"body"`
+	IsDraft bool   `json:"isDraft"`
+}
+
+// OpenPullRequest returns the open pull request whose head is branch, or nil
+func OpenPullRequest(branch string) (*PullRequest, error) {
+	output, err := run("pr", "list", "--head", branch, "--state", "open", "--limit", "1", "--json", "number,title,url,state")
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []PullRequest
+	if err := json.Unmarshal([]byte(output), &prs); err != nil {
+		return nil, fmt.Errorf("unexpected gh output: %w", err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return &prs[0], nil
+}
+
+// LatestPullRequest returns the most recent pull request in any state
+// whose head is branch, or nil
+func LatestPullRequest(branch string) (*PullRequest, error) {
+	output, err := run("pr", "list", "--head", branch, "--state", "all", "--limit", "1", "--json", "number,title,url,state,baseRefName,headRefOid")
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []PullRequest
+	if err := json.Unmarshal([]byte(output), &prs); err != nil {
+		return nil, fmt.Errorf("unexpected gh output: %w", err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return &prs[0], nil
+}
+
+// PullRequestMsg carries the open pull request for a branch, nil when none
+type PullRequestMsg struct {
+	Branch      string
+	PullRequest *PullRequest
+	Err         error
+}
+
+// LookupPullRequest finds the open pull request for branch in the background
+func LookupPullRequest(branch string) tea.Cmd {
+	return func() tea.Msg {
+		pr, err := OpenPullRequest(branch)
+		return PullRequestMsg{Branch: branch, PullRequest: pr, Err: err}
+	}
+}
+
+// run executes gh and returns its standard output
+func run(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			line, _, _ := strings.Cut(message, "\n")
+			return stdout.String(), fmt.Errorf("gh %s: %s", args[0], line)
+		}
+		return stdout.String(), fmt.Errorf("gh %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Issue is the subset of gh's issue fields the orchestrator uses
+type Issue struct {
+	Number    int     `json:"number"`
+	Title     string  `json:"title"`
+	URL       string  `json:"url"`
+	State     string  `json:"state"`
+	Body      string  `json:"body"`
+	Labels    []Label `json:"labels"`
+	Assignees []User  `json:"assignees"`
+}
+
+// Label is an issue label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// User is a GitHub account
+type User struct {
+	Login string `json:"login"`
+}
+
+// IsBug reports whether a label marks the issue as a bug, e.g. "bug" or
+// "type: bug"
+func (i Issue) IsBug() bool {
+	for _, label := range i.Labels {
+		if strings.Contains(strings.ToLower(label.Name), "bug") {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignedTo reports whether login is among the issue's assignees
+func (i Issue) AssignedTo(login string) bool {
+	for _, user := range i.Assignees {
+		if strings.EqualFold(user.Login, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// issueReference matches "123", "#123" and issue URLs
+var issueReference = regexp.MustCompile(`^(?:#?(\d+)|https?://\S+/issues/(\d+)/?)$`)
+
+// ParseIssueNumber reads an issue number from "123", "#123" or an issue URL
+func ParseIssueNumber(reference string) (int, error) {
+	match := issueReference.FindStringSubmatch(strings.TrimSpace(reference))
+	if match == nil {
+		return 0, fmt.Errorf("%q is not an issue number or URL", reference)
+	}
+	digits := match[1] + match[2]
+	return strconv.Atoi(digits)
+}
+
+// ViewIssue fetches an issue of the current repository
+func ViewIssue(number int) (*Issue, error) {
+	output, err := run("issue", "view", strconv.Itoa(number), "--json", "number,title,url,state,body,labels,assignees")
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	if err := json.Unmarshal([]byte(output), &issue); err != nil {
+		return nil, fmt.Errorf("unexpected gh output: %w", err)
+	}
+	return &issue, nil
+}
+
+// CurrentUser returns the login gh is authenticated as
+func CurrentUser() (string, error) {
+	output, err := run("api", "user", "--jq", ".login")
+	return strings.TrimSpace(output), err
+}
+
+// AssignIssueToSelf adds the authenticated user to an issue's assignees
+func AssignIssueToSelf(number int) error {
+	_, err := run("issue", "edit", strconv.Itoa(number), "--add-assignee", "@me")
+	return err
+}
+
+// CloseIssue closes an issue, leaving comment on it when not empty
+func CloseIssue(number int, comment string) error {
+	args := []string{"issue", "close", strconv.Itoa(number)}
+	if comment != "" {
+		args = append(args, "--comment", comment)
+	}
+	_, err := run(args...)
+	return err
+}
+
+// AppendIssueReference ends text with "resolves #N" so GitHub closes the
+// issue, unless there is no issue or text already mentions it. It matches
+// append_issue_reference in utils/git/issue_link.zsh.
+func AppendIssueReference(text string, issue int) string {
+	if issue == 0 {
+		return text
+	}
+	mention := regexp.MustCompile(fmt.Sprintf(`#%d([^0-9]|$)`, issue))
+	if mention.MatchString(text) {
+		return text
+	}
+	return fmt.Sprintf("%s\n\nresolves #%d", text, issue)
+}
@@ -0,0 +1,263 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gemini-orchestrator/internal/sanitize"
+)
+
+// OperationKind is what an issue operation does
+type OperationKind string
+
+// The issue operations auto-issue offers, plus labelling and assigning
+const (
+	OperationCreate  OperationKind = "create"
+	OperationEdit    OperationKind = "edit"
+	OperationComment OperationKind = "comment"
+	OperationView    OperationKind = "view"
+	OperationClose   OperationKind = "close"
+	OperationReopen  OperationKind = "reopen"
+	OperationLabel   OperationKind = "label"
+	OperationAssign  OperationKind = "assign"
+)
+
+// OperationKinds lists the valid kinds, for prompts and error messages
+var OperationKinds = []OperationKind{OperationCreate, OperationEdit, OperationComment, OperationView,
+	OperationClose, OperationReopen, OperationLabel, OperationAssign}
+
+// Close reasons gh accepts
+var closeReasons = []string{"completed", "not planned"}
+
+// Operation is an issue operation as requested in natural language and
+// written by the model as JSON. Which fields apply depends on Kind; Validate
+// rejects missing and inapplicable ones.
+type Operation struct {
+	Kind            OperationKind `json:"operation"`
+	Number          int           `json:"number,omitempty"`           // Every kind but create
+	Title           string        `json:"title,omitempty"`            // create, edit
+	Body            string        `json:"body,omitempty"`             // create, edit, and the comment of comment, close and reopen
+	Reason          string        `json:"reason,omitempty"`           // close: "completed" or "not planned"
+	AddLabels       []string      `json:"add_labels,omitempty"`       // create, edit, label
+	RemoveLabels    []string      `json:"remove_labels,omitempty"`    // edit, label
+	AddAssignees    []string      `json:"add_assignees,omitempty"`    // create, edit, assign; "@me" is the authenticated user
+	RemoveAssignees []string      `json:"remove_assignees,omitempty"` // edit, assign
+}
+
+// ParseOperation reads and validates the operation in a model's response
+func ParseOperation(output string) (*Operation, error) {
+	var op Operation
+	if err := sanitize.ParseJSON(output, &op); err != nil {
+		return nil, err
+	}
+	op.normalize()
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// normalize trims the fields and drops empty list entries
+func (op *Operation) normalize() {
+	op.Kind = OperationKind(strings.ToLower(strings.TrimSpace(string(op.Kind))))
+	op.Title = strings.TrimSpace(op.Title)
+	op.Body = strings.TrimSpace(op.Body)
+	op.Reason = strings.ToLower(strings.TrimSpace(op.Reason))
+	for _, list := range []*[]string{&op.AddLabels, &op.RemoveLabels, &op.AddAssignees, &op.RemoveAssignees} {
+		names := (*list)[:0]
+		for _, name := range *list {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		*list = names
+	}
+}
+
+// Validate checks that the fields the kind needs are there and that no
+// field is set that the kind would silently ignore
+func (op *Operation) Validate() error {
+	if !slices.Contains(OperationKinds, op.Kind) {
+		return fmt.Errorf("unknown operation %q, expected one of %s", op.Kind, joinKinds())
+	}
+	if op.Kind == OperationCreate {
+		if op.Number != 0 {
+			return fmt.Errorf("create does not take an issue number")
+		}
+	} else if op.Number <= 0 {
+		return fmt.Errorf("%s needs the number of an issue", op.Kind)
+	}
+	if strings.Contains(op.Title, "\n") {
+		return fmt.Errorf("the title spans several lines")
+	}
+
+	set := map[string]bool{
+		"title":            op.Title != "",
+		"body":             op.Body != "",
+		"reason":           op.Reason != "",
+		"add_labels":       len(op.AddLabels) > 0,
+		"remove_labels":    len(op.RemoveLabels) > 0,
+		"add_assignees":    len(op.AddAssignees) > 0,
+		"remove_assignees": len(op.RemoveAssignees) > 0,
+	}
+	var allowed []string
+	switch op.Kind {
+	case OperationCreate:
+		allowed = []string{"title", "body", "add_labels", "add_assignees"}
+		if !set["title"] {
+			return fmt.Errorf("create needs a title")
+		}
+	case OperationEdit:
+		allowed = []string{"title", "body", "add_labels", "remove_labels", "add_assignees", "remove_assignees"}
+	case OperationComment:
+		allowed = []string{"body"}
+		if !set["body"] {
+			return fmt.Errorf("comment needs a body")
+		}
+	case OperationClose:
+		allowed = []string{"body", "reason"}
+		if set["reason"] && !slices.Contains(closeReasons, op.Reason) {
+			return fmt.Errorf("unknown close reason %q, expected %q or %q", op.Reason, closeReasons[0], closeReasons[1])
+		}
+	case OperationReopen:
+		allowed = []string{"body"}
+	case OperationLabel:
+		allowed = []string{"add_labels", "remove_labels"}
+	case OperationAssign:
+		allowed = []string{"add_assignees", "remove_assignees"}
+	}
+
+	changes := false
+	for _, field := range []string{"title", "body", "reason", "add_labels", "remove_labels", "add_assignees", "remove_assignees"} {
+		if !set[field] {
+			continue
+		}
+		if !slices.Contains(allowed, field) {
+			return fmt.Errorf("%s does not take %s", op.Kind, field)
+		}
+		changes = true
+	}
+	if !changes && (op.Kind == OperationEdit || op.Kind == OperationLabel || op.Kind == OperationAssign) {
+		return fmt.Errorf("%s of #%d changes nothing", op.Kind, op.Number)
+	}
+	return nil
+}
+
+func joinKinds() string {
+	kinds := make([]string, len(OperationKinds))
+	for i, kind := range OperationKinds {
+		kinds[i] = string(kind)
+	}
+	return strings.Join(kinds, ", ")
+}
+
+// Changes reports whether the operation modifies GitHub, which is every
+// kind but view
+func (op *Operation) Changes() bool {
+	return op.Kind != OperationView
+}
+
+// Commands returns the gh arguments that carry out the operation. View is
+// done by ViewIssue instead and has none.
+func (op *Operation) Commands() [][]string {
+	number := strconv.Itoa(op.Number)
+	switch op.Kind {
+	case OperationCreate:
+		args := []string{"issue", "create", "--title", op.Title, "--body", op.Body}
+		args = appendList(args, "--label", op.AddLabels)
+		return [][]string{appendList(args, "--assignee", op.AddAssignees)}
+	case OperationEdit, OperationLabel, OperationAssign:
+		args := []string{"issue", "edit", number}
+		if op.Title != "" {
+			args = append(args, "--title", op.Title)
+		}
+		if op.Body != "" {
+			args = append(args, "--body", op.Body)
+		}
+		args = appendList(args, "--add-label", op.AddLabels)
+		args = appendList(args, "--remove-label", op.RemoveLabels)
+		args = appendList(args, "--add-assignee", op.AddAssignees)
+		return [][]string{appendList(args, "--remove-assignee", op.RemoveAssignees)}
+	case OperationComment:
+		return [][]string{{"issue", "comment", number, "--body", op.Body}}
+	case OperationClose, OperationReopen:
+		args := []string{"issue", string(op.Kind), number}
+		if op.Body != "" {
+			args = append(args, "--comment", op.Body)
+		}
+		if op.Reason != "" {
+			args = append(args, "--reason", op.Reason)
+		}
+		return [][]string{args}
+	}
+	return nil
+}
+
+func appendList(args []string, flag string, values []string) []string {
+	if len(values) == 0 {
+		return args
+	}
+	return append(args, flag, strings.Join(values, ","))
+}
+
+// Execute runs the operation's gh commands and returns the URL gh printed
+// last, which for create is the new issue's
+func (op *Operation) Execute() (string, error) {
+	var output string
+	for _, args := range op.Commands() {
+		var err error
+		if output, err = run(args...); err != nil {
+			return "", err
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if url := strings.TrimSpace(lines[len(lines)-1]); strings.HasPrefix(url, "https://") {
+		return url, nil
+	}
+	return "", nil
+}
+
+// Summary describes the operation in one line, e.g. "Close #12 as not
+// planned"
+func (op *Operation) Summary() string {
+	switch op.Kind {
+	case OperationCreate:
+		return "Create issue: " + op.Title
+	case OperationEdit:
+		return fmt.Sprintf("Edit #%d", op.Number)
+	case OperationComment:
+		return fmt.Sprintf("Comment on #%d", op.Number)
+	case OperationView:
+		return fmt.Sprintf("View #%d", op.Number)
+	case OperationClose:
+		if op.Reason != "" {
+			return fmt.Sprintf("Close #%d as %s", op.Number, op.Reason)
+		}
+		return fmt.Sprintf("Close #%d", op.Number)
+	case OperationReopen:
+		return fmt.Sprintf("Reopen #%d", op.Number)
+	case OperationLabel:
+		return fmt.Sprintf("Change the labels of #%d", op.Number)
+	case OperationAssign:
+		return fmt.Sprintf("Change the assignees of #%d", op.Number)
+	}
+	return string(op.Kind)
+}
+
+// ListLabels returns the names of the repository's labels
+func ListLabels() ([]string, error) {
+	output, err := run("label", "list", "--limit", "100", "--json", "name", "--jq", ".[].name")
+	if err != nil {
+		return nil, err
+	}
+	// One name per line; names may contain spaces, as in "good first issue"
+	var labels []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			labels = append(labels, line)
+		}
+	}
+	return labels, nil
+}
@@ -0,0 +1,197 @@
+package github
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected Operation
+	}{
+		{
+			name:     "create",
+			output:   `{"operation": "create", "title": "Flaky upload test", "body": "Fails on CI", "add_labels": ["bug"]}`,
+			expected: Operation{Kind: OperationCreate, Title: "Flaky upload test", Body: "Fails on CI", AddLabels: []string{"bug"}},
+		},
+		{
+			name:     "fenced, with kind and reason normalised",
+			output:   "```json\n{\"operation\": \" Close \", \"number\": 12, \"reason\": \"Not Planned\"}\n```",
+			expected: Operation{Kind: OperationClose, Number: 12, Reason: "not planned"},
+		},
+		{
+			name:   "lists are trimmed and empty entries dropped",
+			output: `{"operation": "label", "number": 8, "add_labels": [" bug ", "", "good first issue"], "remove_labels": ["  "]}`,
+			expected: Operation{Kind: OperationLabel, Number: 8, AddLabels: []string{"bug", "good first issue"},
+				RemoveLabels: []string{}},
+		},
+		{
+			name:     "view takes only a number",
+			output:   `{"operation": "view", "number": 3}`,
+			expected: Operation{Kind: OperationView, Number: 3},
+		},
+		{
+			name:     "reopen without a comment",
+			output:   `{"operation": "reopen", "number": 4}`,
+			expected: Operation{Kind: OperationReopen, Number: 4},
+		},
+	}
+
+	for _, test := range tests {
+		op, err := ParseOperation(test.output)
+		if err != nil {
+			t.Errorf("%s: ParseOperation() failed: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(&test.expected, op) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, *op)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Operation
+		err  string // Empty when valid
+	}{
+		{"unknown kind", Operation{Kind: "delete", Number: 1}, `unknown operation "delete", expected one of create, edit, comment, view, close, reopen, label, assign`},
+		{"create with a number", Operation{Kind: OperationCreate, Number: 5, Title: "x"}, "create does not take an issue number"},
+		{"create without a title", Operation{Kind: OperationCreate, Body: "x"}, "create needs a title"},
+		{"create with a multi-line title", Operation{Kind: OperationCreate, Title: "one\ntwo"}, "the title spans several lines"},
+		{"create removing labels", Operation{Kind: OperationCreate, Title: "x", RemoveLabels: []string{"bug"}}, "create does not take remove_labels"},
+		{"edit without a number", Operation{Kind: OperationEdit, Title: "x"}, "edit needs the number of an issue"},
+		{"negative number", Operation{Kind: OperationView, Number: -1}, "view needs the number of an issue"},
+		{"edit with no changes", Operation{Kind: OperationEdit, Number: 2}, "edit of #2 changes nothing"},
+		{"label with no changes", Operation{Kind: OperationLabel, Number: 2}, "label of #2 changes nothing"},
+		{"assign with no changes", Operation{Kind: OperationAssign, Number: 2}, "assign of #2 changes nothing"},
+		{"label with a title", Operation{Kind: OperationLabel, Number: 2, Title: "x", AddLabels: []string{"bug"}}, "label does not take title"},
+		{"assign with labels", Operation{Kind: OperationAssign, Number: 2, AddLabels: []string{"bug"}}, "assign does not take add_labels"},
+		{"comment without a body", Operation{Kind: OperationComment, Number: 2}, "comment needs a body"},
+		{"comment with a title", Operation{Kind: OperationComment, Number: 2, Body: "x", Title: "y"}, "comment does not take title"},
+		{"close with a bad reason", Operation{Kind: OperationClose, Number: 2, Reason: "wontfix"}, `unknown close reason "wontfix", expected "completed" or "not planned"`},
+		{"reopen with a reason", Operation{Kind: OperationReopen, Number: 2, Reason: "completed"}, "reopen does not take reason"},
+		{"view with a body", Operation{Kind: OperationView, Number: 2, Body: "x"}, "view does not take body"},
+
+		{"close with a reason and comment", Operation{Kind: OperationClose, Number: 2, Reason: "completed", Body: "Done"}, ""},
+		{"close alone", Operation{Kind: OperationClose, Number: 2}, ""},
+		{"assign to me", Operation{Kind: OperationAssign, Number: 2, AddAssignees: []string{"@me"}}, ""},
+		{"edit removing assignees", Operation{Kind: OperationEdit, Number: 2, RemoveAssignees: []string{"octocat"}}, ""},
+	}
+
+	for _, test := range tests {
+		err := test.op.Validate()
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: expected no error, got %q", test.name, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: expected error %q, got none", test.name, test.err)
+		case err != nil && err.Error() != test.err:
+			t.Errorf("%s: expected error %q, got %q", test.name, test.err, err)
+		}
+	}
+}
+
+func TestParseOperationErrors(t *testing.T) {
+	for _, output := range []string{
+		"not json at all",
+		`{"operation": "close", "number": 12, "reason": "duplicate"}`,
+		`{"operation": "create", "number": 1, "title": "x"}`,
+		`{"operation": "label", "number": 8, "add_labels": ["", " "]}`,
+	} {
+		if _, err := ParseOperation(output); err == nil {
+			t.Errorf("ParseOperation(%q) should fail", output)
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       Operation
+		expected [][]string
+	}{
+		{
+			name: "create",
+			op:   Operation{Kind: OperationCreate, Title: "Flaky test", Body: "Fails", AddLabels: []string{"bug", "good first issue"}, AddAssignees: []string{"@me"}},
+			expected: [][]string{{"issue", "create", "--title", "Flaky test", "--body", "Fails",
+				"--label", "bug,good first issue", "--assignee", "@me"}},
+		},
+		{
+			name: "edit",
+			op: Operation{Kind: OperationEdit, Number: 7, Title: "New title", AddLabels: []string{"bug"},
+				RemoveLabels: []string{"triage"}, AddAssignees: []string{"a"}, RemoveAssignees: []string{"b", "c"}},
+			expected: [][]string{{"issue", "edit", "7", "--title", "New title", "--add-label", "bug",
+				"--remove-label", "triage", "--add-assignee", "a", "--remove-assignee", "b,c"}},
+		},
+		{
+			name:     "label",
+			op:       Operation{Kind: OperationLabel, Number: 8, AddLabels: []string{"bug"}},
+			expected: [][]string{{"issue", "edit", "8", "--add-label", "bug"}},
+		},
+		{
+			name:     "assign",
+			op:       Operation{Kind: OperationAssign, Number: 9, RemoveAssignees: []string{"@me"}},
+			expected: [][]string{{"issue", "edit", "9", "--remove-assignee", "@me"}},
+		},
+		{
+			name:     "comment",
+			op:       Operation{Kind: OperationComment, Number: 3, Body: "Looks good; \"quoted\" $HOME"},
+			expected: [][]string{{"issue", "comment", "3", "--body", "Looks good; \"quoted\" $HOME"}},
+		},
+		{
+			name:     "close as not planned with a comment",
+			op:       Operation{Kind: OperationClose, Number: 12, Reason: "not planned", Body: "Out of scope"},
+			expected: [][]string{{"issue", "close", "12", "--comment", "Out of scope", "--reason", "not planned"}},
+		},
+		{
+			name:     "close alone",
+			op:       Operation{Kind: OperationClose, Number: 12},
+			expected: [][]string{{"issue", "close", "12"}},
+		},
+		{
+			name:     "reopen with a comment",
+			op:       Operation{Kind: OperationReopen, Number: 12, Body: "Still happens"},
+			expected: [][]string{{"issue", "reopen", "12", "--comment", "Still happens"}},
+		},
+		{
+			name: "view is done by ViewIssue",
+			op:   Operation{Kind: OperationView, Number: 1},
+		},
+	}
+
+	for _, test := range tests {
+		if err := test.op.Validate(); err != nil {
+			t.Errorf("%s: invalid test operation: %v", test.name, err)
+		}
+		if actual := test.op.Commands(); !reflect.DeepEqual(test.expected, actual) {
+			t.Errorf("%s: expected\n%q\ngot\n%q", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := map[string]Operation{
+		"Create issue: Flaky test":   {Kind: OperationCreate, Title: "Flaky test"},
+		"Close #12 as not planned":   {Kind: OperationClose, Number: 12, Reason: "not planned"},
+		"Close #12":                  {Kind: OperationClose, Number: 12},
+		"Change the labels of #8":    {Kind: OperationLabel, Number: 8},
+		"Change the assignees of #9": {Kind: OperationAssign, Number: 9},
+		"Comment on #3":              {Kind: OperationComment, Number: 3},
+		"View #1":                    {Kind: OperationView, Number: 1},
+	}
+	for expected, op := range tests {
+		if actual := op.Summary(); actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	}
+	if (&Operation{Kind: OperationView}).Changes() || !(&Operation{Kind: OperationComment}).Changes() {
+		t.Errorf("only view should leave GitHub unchanged")
+	}
+	if !strings.Contains(joinKinds(), "assign") {
+		t.Errorf("joinKinds() should list every kind, got %q", joinKinds())
+	}
+}
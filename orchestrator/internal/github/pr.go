@@ -0,0 +1,83 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PullRequestOptions describes a pull request to create or update
+type PullRequestOptions struct {
+	Title     string
+	Body      string
+	Base      string
+	Head      string
+	Draft     bool
+	Reviewers []string // Logins or org/team names
+	Labels    []string
+}
+
+// ViewPullRequest returns a pull request with its body and draft state
+func ViewPullRequest(number int) (*PullRequest, error) {
+	output, err := run("pr", "view", strconv.Itoa(number), "--json", "number,title,url,state,baseRefName,headRefOid,body,isDraft")
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if err := json.Unmarshal([]byte(output), &pr); err != nil {
+		return nil, fmt.Errorf("unexpected gh output: %w", err)
+	}
+	return &pr, nil
+}
+
+// CreatePullRequest opens a pull request assigned to the authenticated
+// user, as auto-pr does, and returns its URL
+func CreatePullRequest(opts PullRequestOptions) (string, error) {
+	args := []string{"pr", "create", "--title", opts.Title, "--body", opts.Body,
+		"--base", opts.Base, "--head", opts.Head, "--assignee", "@me"}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	if len(opts.Reviewers) > 0 {
+		args = append(args, "--reviewer", strings.Join(opts.Reviewers, ","))
+	}
+	if len(opts.Labels) > 0 {
+		args = append(args, "--label", strings.Join(opts.Labels, ","))
+	}
+
+	output, err := run(args...)
+	if err != nil {
+		return "", err
+	}
+	// gh prints the URL of the new pull request last
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// EditPullRequest replaces the title and body of a pull request, adds
+// reviewers and labels, and marks it draft or ready when that changes
+func EditPullRequest(pr *PullRequest, opts PullRequestOptions) error {
+	number := strconv.Itoa(pr.Number)
+	args := []string{"pr", "edit", number, "--title", opts.Title, "--body", opts.Body}
+	if len(opts.Reviewers) > 0 {
+		args = append(args, "--add-reviewer", strings.Join(opts.Reviewers, ","))
+	}
+	if len(opts.Labels) > 0 {
+		args = append(args, "--add-label", strings.Join(opts.Labels, ","))
+	}
+	if _, err := run(args...); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.Draft && !pr.IsDraft:
+		_, err := run("pr", "ready", number, "--undo")
+		return err
+	case !opts.Draft && pr.IsDraft:
+		_, err := run("pr", "ready", number)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Package highlight provides lightweight, line-based syntax highlighting
+// for the diff viewer. It recognises keywords, strings, comments and
+// numbers for common languages without pulling in a full lexer library;
+// constructs spanning several lines (block comments, heredocs) are only
+// highlighted on the line they start.
+package highlight
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind classifies a token
+type Kind int
+
+const (
+	Plain Kind = iota
+	Keyword
+	String
+	Comment
+	Number
+)
+
+// Token is a run of text of one kind; Start and End are byte offsets
+type Token struct {
+	Kind  Kind
+	Start int
+	End   int
+}
+
+// Language describes how to tokenize one language
+type Language struct {
+	Name         string
+	Keywords     map[string]bool
+	LineComments []string // e.g. "//", "#"
+	BlockComment [2]string
+	Quotes       string // Characters that open and close strings
+}
+
+func words(list string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	golang = &Language{
+		Name: "go",
+		Keywords: words(`break case chan const continue default defer else fallthrough for func go goto if
+			import interface map package range return select struct switch type var
+			nil true false iota error string int int64 int32 uint byte rune bool float64 any`),
+		LineComments: []string{"//"},
+		BlockComment: [2]string{"/*", "*/"},
+		Quotes:       "\"`'",
+	}
+	python = &Language{
+		Name: "python",
+		Keywords: words(`and as assert async await break class continue def del elif else except finally
+			for from global if import in is lambda nonlocal not or pass raise return try while
+			with yield None True False self`),
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	}
+	javascript = &Language{
+		Name: "javascript",
+		Keywords: words(`async await break case catch class const continue default delete do else export
+			extends finally for from function if import in instanceof let new of return static super
+			switch this throw try typeof var void while yield null undefined true false
+			interface type enum implements readonly`),
+		LineComments: []string{"//"},
+		BlockComment: [2]string{"/*", "*/"},
+		Quotes:       "\"'`",
+	}
+	shell = &Language{
+		Name: "shell",
+		Keywords: words(`if then else elif fi case esac for while until do done in function return local
+			export readonly source echo exit set unset shift true false`),
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	}
+	cFamily = &Language{
+		Name: "c",
+		Keywords: words(`auto break case catch char class const continue default delete do double else enum
+			extern final float for fn if impl import int let long loop match mod mut namespace new
+			package private protected pub public return self short static struct super switch this
+			throw throws trait try typedef union unsafe use using var void volatile where while
+			true false null nullptr`),
+		LineComments: []string{"//"},
+		BlockComment: [2]string{"/*", "*/"},
+		Quotes:       `"'`,
+	}
+	ruby = &Language{
+		Name: "ruby",
+		Keywords: words(`begin break case class def do else elsif end ensure false for if in module next
+			nil not or redo rescue retry return self super then true unless until when while yield`),
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	}
+	config = &Language{
+		Name:         "config",
+		Keywords:     words(`true false null yes no on off`),
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	}
+	json = &Language{
+		Name:     "json",
+		Keywords: words(`true false null`),
+		Quotes:   `"`,
+	}
+)
+
+var byExtension = map[string]*Language{
+	".go": golang,
+	".py": python, ".rb": ruby,
+	".js": javascript, ".jsx": javascript, ".ts": javascript, ".tsx": javascript, ".mjs": javascript,
+	".sh": shell, ".bash": shell, ".zsh": shell,
+	".c": cFamily, ".h": cFamily, ".cc": cFamily, ".cpp": cFamily, ".hpp": cFamily,
+	".java": cFamily, ".kt": cFamily, ".cs": cFamily, ".rs": cFamily, ".swift": cFamily,
+	".yml": config, ".yaml": config, ".toml": config, ".ini": config,
+	".json": json,
+}
+
+var byName = map[string]*Language{
+	"Makefile":       config,
+	"Dockerfile":     config,
+	".gitignore":     config,
+	".gemini-config": config,
+	".zshrc":         shell,
+	".bashrc":        shell,
+}
+
+// Detect picks a language from a file name, or nil when unknown
+func Detect(path string) *Language {
+	name := filepath.Base(path)
+	if lang, ok := byName[name]; ok {
+		return lang
+	}
+	return byExtension[strings.ToLower(filepath.Ext(name))]
+}
+
+// Tokenize splits line into tokens; a nil language yields one plain token
+func Tokenize(lang *Language, line string) []Token {
+	if lang == nil || line == "" {
+		return []Token{{Kind: Plain, Start: 0, End: len(line)}}
+	}
+
+	var tokens []Token
+	emit := func(kind Kind, start, end int) {
+		// Merge adjacent tokens of the same kind
+		if n := len(tokens); n > 0 && tokens[n-1].Kind == kind && tokens[n-1].End == start {
+			tokens[n-1].End = end
+			return
+		}
+		tokens = append(tokens, Token{Kind: kind, Start: start, End: end})
+	}
+
+	for i := 0; i < len(line); {
+		rest := line[i:]
+
+		if lang.isLineComment(line, i) {
+			emit(Comment, i, len(line))
+			break
+		}
+		if open := lang.BlockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], lang.BlockComment[1])
+			if end < 0 {
+				emit(Comment, i, len(line))
+				break
+			}
+			stop := i + len(open) + end + len(lang.BlockComment[1])
+			emit(Comment, i, stop)
+			i = stop
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case strings.ContainsRune(lang.Quotes, r):
+			stop := closingQuote(line, i+size, r)
+			emit(String, i, stop)
+			i = stop
+		case unicode.IsDigit(r) && (i == 0 || !isWordByte(line[i-1])):
+			stop := i
+			for stop < len(line) && (isWordByte(line[stop]) || line[stop] == '.') {
+				stop++
+			}
+			emit(Number, i, stop)
+			i = stop
+		case isWordStart(r):
+			stop := i
+			for stop < len(line) && isWordByte(line[stop]) {
+				stop++
+			}
+			if lang.Keywords[line[i:stop]] {
+				emit(Keyword, i, stop)
+			} else {
+				emit(Plain, i, stop)
+			}
+			i = stop
+		default:
+			emit(Plain, i, i+size)
+			i += size
+		}
+	}
+	return tokens
+}
+
+// isLineComment reports whether a line comment starts at i. A "#" only
+// starts a comment at the beginning of a word, so "$#" and "${#x}" in
+// shell scripts are not comments.
+func (lang *Language) isLineComment(line string, i int) bool {
+	for _, prefix := range lang.LineComments {
+		if !strings.HasPrefix(line[i:], prefix) {
+			continue
+		}
+		if prefix == "#" && i > 0 && !unicode.IsSpace(rune(line[i-1])) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// closingQuote returns the offset after the quote closing a string that
+// opened before start, or the end of the line
+func closingQuote(line string, start int, quote rune) int {
+	for i := start; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		switch {
+		case r == '\\' && quote != '`':
+			i += size + 1
+		case r == quote:
+			return i + size
+		default:
+			i += size
+		}
+	}
+	return len(line)
+}
+
+func isWordStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 0x80 || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,111 @@
+package highlight
+
+import "unicode/utf8"
+
+// Range is a byte range [Start, End) within a line
+type Range struct {
+	Start int
+	End   int
+}
+
+// maxWordDiffCells bounds the LCS table so very long lines stay cheap
+const maxWordDiffCells = 250000
+
+// WordDiff compares the before and after version of a line word by word and
+// returns the changed ranges of each. ok is false when the lines have too
+// little in common for intra-line highlights to be useful; the whole line
+// is then the change.
+func WordDiff(before, after string) (oldRanges, newRanges []Range, ok bool) {
+	a, b := splitWords(before), splitWords(after)
+	if len(a)*len(b) > maxWordDiffCells {
+		return nil, nil, false
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i].text(before) == b[j].text(after) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	common := 0
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].text(before) == b[j].text(after):
+			if !a[i].space {
+				common += a[i].End - a[i].Start
+			}
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			oldRanges = appendRange(oldRanges, a[i].Range)
+			i++
+		default:
+			newRanges = appendRange(newRanges, b[j].Range)
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		oldRanges = appendRange(oldRanges, a[i].Range)
+	}
+	for ; j < len(b); j++ {
+		newRanges = appendRange(newRanges, b[j].Range)
+	}
+
+	// Highlighting most of a line is noise, the line colour already says it
+	if common*3 < max(len(before), len(after)) {
+		return nil, nil, false
+	}
+	return oldRanges, newRanges, true
+}
+
+// appendRange adds r, merging it with the previous range when they touch
+func appendRange(ranges []Range, r Range) []Range {
+	if n := len(ranges); n > 0 && ranges[n-1].End == r.Start {
+		ranges[n-1].End = r.End
+		return ranges
+	}
+	return append(ranges, r)
+}
+
+// word is a run of identifier characters, a run of spaces, or a single
+// other character
+type word struct {
+	Range
+	space bool
+}
+
+func (w word) text(line string) string {
+	return line[w.Start:w.End]
+}
+
+func splitWords(line string) []word {
+	var result []word
+	for i := 0; i < len(line); {
+		start := i
+		switch {
+		case isWordByte(line[i]):
+			for i < len(line) && isWordByte(line[i]) {
+				i++
+			}
+		case line[i] == ' ' || line[i] == '\t':
+			for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+				i++
+			}
+		default:
+			_, size := utf8.DecodeRuneInString(line[i:])
+			i += size
+		}
+		result = append(result, word{Range: Range{start, i}, space: line[start] == ' ' || line[start] == '\t'})
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package highlight
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    string
+		after     string
+		oldRanges []Range
+		newRanges []Range
+		ok        bool
+	}{
+		{
+			name:   "unchanged",
+			before: "return a + b",
+			after:  "return a + b",
+			ok:     true,
+		},
+		{
+			name:      "an operator",
+			before:    "return a + b",
+			after:     "return a - b",
+			oldRanges: []Range{{9, 10}},
+			newRanges: []Range{{9, 10}},
+			ok:        true,
+		},
+		{
+			name:      "a renamed identifier is one word",
+			before:    "value := compute(count)",
+			after:     "value := compute(total)",
+			oldRanges: []Range{{17, 22}},
+			newRanges: []Range{{17, 22}},
+			ok:        true,
+		},
+		{
+			name:      "an added argument is one range",
+			before:    "foo(a, b)",
+			after:     "foo(a, b, c)",
+			newRanges: []Range{{8, 11}},
+			ok:        true,
+		},
+		{
+			name:      "a removed argument is one range",
+			before:    "foo(a, b, c)",
+			after:     "foo(a, c)",
+			oldRanges: []Range{{7, 10}},
+			ok:        true,
+		},
+		{
+			name:      "indentation",
+			before:    "\tx = 1",
+			after:     "    x = 1",
+			oldRanges: []Range{{0, 1}},
+			newRanges: []Range{{0, 4}},
+			ok:        true,
+		},
+		{
+			name:      "multi-byte letters stay in their word",
+			before:    `msg := "café au lait"`,
+			after:     `msg := "thé au lait"`,
+			oldRanges: []Range{{8, 13}},
+			newRanges: []Range{{8, 12}},
+			ok:        true,
+		},
+		{
+			name:   "too little in common",
+			before: "count := 0",
+			after:  "total := 1",
+		},
+		{
+			name:   "an added line",
+			before: "",
+			after:  "fmt.Println()",
+		},
+	}
+
+	for _, test := range tests {
+		oldRanges, newRanges, ok := WordDiff(test.before, test.after)
+		if ok != test.ok {
+			t.Errorf("%s: expected ok %v, got %v", test.name, test.ok, ok)
+		}
+		if !reflect.DeepEqual(test.oldRanges, oldRanges) {
+			t.Errorf("%s: expected old ranges %v, got %v", test.name, test.oldRanges, oldRanges)
+		}
+		if !reflect.DeepEqual(test.newRanges, newRanges) {
+			t.Errorf("%s: expected new ranges %v, got %v", test.name, test.newRanges, newRanges)
+		}
+	}
+}
+
+// TestWordDiffLongLines checks lines too long for the LCS table are
+// highlighted whole, however much they have in common
+func TestWordDiffLongLines(t *testing.T) {
+	before := strings.Repeat("a ", 600)
+	after := before + "b"
+	if _, _, ok := WordDiff(before, after); ok {
+		t.Errorf("expected lines of over %d words to be compared whole", len(splitWords(before)))
+	}
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"strings"
+
+	"gemini-orchestrator/internal/sanitize"
+)
+
+// noticeFilter removes CLI noise from a response arriving in pieces, as
+// sanitize.Clean does for a whole one. A line is held back only while its
+// start could still be a notice, so the answer itself streams unchanged.
+// Leading blank space is dropped.
+type noticeFilter struct {
+	line    strings.Builder // Held back start of the current line
+	passing bool            // The current line is known not to be noise
+	started bool            // Some non-blank output has been passed on
+}
+
+func (f *noticeFilter) write(text string) string {
+	var out strings.Builder
+	for {
+		chunk, rest, newline := strings.Cut(text, "\n")
+		switch {
+		case f.passing:
+			out.WriteString(chunk)
+		case newline:
+			f.line.WriteString(chunk)
+			line := f.line.String()
+			f.line.Reset()
+			if sanitize.IsNoise(line) {
+				// Drop the line with its newline
+				text = rest
+				continue
+			}
+			out.WriteString(line)
+		default:
+			f.line.WriteString(chunk)
+			if line := f.line.String(); !sanitize.MayBeNoise(line) {
+				out.WriteString(line)
+				f.line.Reset()
+				f.passing = true
+			}
+		}
+		if !newline {
+			break
+		}
+		out.WriteString("\n")
+		f.passing = false
+		text = rest
+	}
+
+	result := out.String()
+	if !f.started {
+		result = strings.TrimLeft(result, " \t\r\n")
+		f.started = result != ""
+	}
+	return result
+}
+
+// pending reports whether the start of a line is held back
+func (f *noticeFilter) pending() bool {
+	return f.line.Len() > 0
+}
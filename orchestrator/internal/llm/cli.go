@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GeminiCLI runs the gemini CLI the same way the zsh scripts do: the prompt
+// as --prompt, the input on standard input
+type GeminiCLI struct{}
+
+// cliModels are offered by /model list; the CLI has no way to list them
+var cliModels = []string{"gemini-2.5-pro", "gemini-2.5-flash", "gemini-2.5-flash-lite"}
+
+func (b *GeminiCLI) Name() string { return "gemini-cli" }
+
+func (b *GeminiCLI) Generate(ctx context.Context, req Request) (string, error) {
+	return collect(ctx, b.Stream(req))
+}
+
+func (b *GeminiCLI) Stream(req Request) *Stream {
+	return startStream(func(ctx context.Context, send func(string)) error {
+		args := []string{"--prompt", req.Prompt}
+		if req.Model != "" {
+			args = append([]string{"-m", req.Model}, args...)
+		}
+
+		// Output goes through a pipe closed when gemini exits, so a killed
+		// process cannot leave the reader blocked on a child's inherited output
+		stdout, writer := io.Pipe()
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "gemini", args...)
+		cmd.Stdin = strings.NewReader(req.Input)
+		cmd.Stdout = writer
+		cmd.Stderr = &stderr
+		cmd.WaitDelay = time.Second
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("gemini: %w", err)
+		}
+
+		exited := make(chan error, 1)
+		go func() {
+			err := cmd.Wait()
+			writer.Close()
+			exited <- err
+		}()
+
+		var pending []byte
+		buffer := make([]byte, 4096)
+		for {
+			n, err := stdout.Read(buffer)
+			pending = append(pending, buffer[:n]...)
+			// Hold back a rune split across reads
+			complete := len(pending)
+			for complete > 0 && !utf8.Valid(pending[:complete]) && len(pending)-complete < utf8.UTFMax {
+				complete--
+			}
+			send(string(pending[:complete]))
+			pending = pending[complete:]
+			if err != nil {
+				break
+			}
+		}
+		send(string(pending))
+
+		if err := <-exited; err != nil && ctx.Err() == nil {
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				line, _, _ := strings.Cut(message, "\n")
+				return fmt.Errorf("gemini: %s", line)
+			}
+			return fmt.Errorf("gemini: %w", err)
+		}
+		return nil
+	})
+}
+
+func (b *GeminiCLI) Models(ctx context.Context) ([]string, error) {
+	return cliModels, nil
+}
+
+func (b *GeminiCLI) EstimateTokens(text string) int {
+	return estimateTokens(text)
+}
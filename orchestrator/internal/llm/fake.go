@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Fake answers without a model, for working offline and exercising
+// workflows: every request gets Reply, or when that is empty a fixed
+// sentence describing the request. The same request always gets the same
+// response, streamed word by word.
+type Fake struct {
+	Reply string
+}
+
+func (b *Fake) Name() string { return "fake" }
+
+func (b *Fake) Generate(ctx context.Context, req Request) (string, error) {
+	return collect(ctx, b.Stream(req))
+}
+
+func (b *Fake) Stream(req Request) *Stream {
+	reply := b.Reply
+	if reply == "" {
+		prompt, _, _ := strings.Cut(strings.TrimSpace(req.Prompt), "\n")
+		reply = fmt.Sprintf("Fake response to %q with %d bytes of input.", truncate(prompt, 60), len(req.Input))
+	}
+	return startStream(func(ctx context.Context, send func(string)) error {
+		for _, word := range strings.SplitAfter(reply, " ") {
+			send(word)
+		}
+		return nil
+	})
+}
+
+func (b *Fake) Models(ctx context.Context) ([]string, error) {
+	return []string{"fake"}, nil
+}
+
+func (b *Fake) EstimateTokens(text string) int {
+	return estimateTokens(text)
+}
+
+func truncate(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	return s[:width-1] + "…"
+}
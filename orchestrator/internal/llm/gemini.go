@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gemini-orchestrator/internal/config"
+)
+
+const defaultGeminiEndpoint = "https://generativelanguage.googleapis.com/v1beta"
+
+// GeminiAPI calls the Gemini HTTP API directly, authenticated with an API
+// key instead of the CLI's login
+type GeminiAPI struct {
+	Endpoint string // Base URL ending in the API version
+	Key      string
+	Client   *http.Client
+}
+
+// geminiResponse is the part of a GenerateContentResponse the backend reads
+type geminiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+}
+
+func (b *GeminiAPI) Name() string { return "gemini-api" }
+
+func (b *GeminiAPI) Generate(ctx context.Context, req Request) (string, error) {
+	return collect(ctx, b.Stream(req))
+}
+
+func (b *GeminiAPI) Stream(req Request) *Stream {
+	return startStream(func(ctx context.Context, send func(string)) error {
+		model := req.Model
+		if model == "" {
+			model = config.Defaults[config.KeyGeminiModel]
+		}
+		body := map[string]any{
+			"contents": []map[string]any{
+				{"role": "user", "parts": []map[string]string{{"text": req.text()}}},
+			},
+		}
+		endpoint := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse", b.Endpoint, url.PathEscape(model))
+		response, err := postJSON(ctx, b.Client, endpoint, b.headers(), body)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		return readEvents(response.Body, func(data string) (bool, error) {
+			var chunk geminiResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return false, fmt.Errorf("unexpected response from the Gemini API: %w", err)
+			}
+			if reason := chunk.PromptFeedback.BlockReason; reason != "" {
+				return false, fmt.Errorf("the Gemini API blocked the prompt (%s)", strings.ToLower(reason))
+			}
+			for _, candidate := range chunk.Candidates {
+				for _, part := range candidate.Content.Parts {
+					send(part.Text)
+				}
+			}
+			return false, nil
+		})
+	})
+}
+
+func (b *GeminiAPI) Models(ctx context.Context) ([]string, error) {
+	var list struct {
+		Models []struct {
+			Name    string   `json:"name"`
+			Methods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := getJSON(ctx, b.Client, b.Endpoint+"/models?pageSize=1000", b.headers(), &list); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, model := range list.Models {
+		for _, method := range model.Methods {
+			if method == "generateContent" {
+				names = append(names, strings.TrimPrefix(model.Name, "models/"))
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+func (b *GeminiAPI) EstimateTokens(text string) int {
+	return estimateTokens(text)
+}
+
+func (b *GeminiAPI) headers() map[string]string {
+	return map[string]string{"x-goog-api-key": b.Key}
+}
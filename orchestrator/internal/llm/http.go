@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// postJSON sends body to url and returns the response for the caller to
+// read; a non-2xx status becomes an error carrying the server's message
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return send(client, request, headers)
+}
+
+// getJSON fetches url and decodes its JSON response into result
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, result any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := send(client, request, headers)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("unexpected response from %s: %w", request.URL.Host, err)
+	}
+	return nil
+}
+
+func send(client *http.Client, request *http.Request, headers map[string]string) (*http.Response, error) {
+	for key, value := range headers {
+		if value != "" {
+			request.Header.Set(key, value)
+		}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode/100 != 2 {
+		defer response.Body.Close()
+		return nil, fmt.Errorf("%s: %s", request.URL.Host, errorMessage(response))
+	}
+	return response, nil
+}
+
+// errorMessage extracts {"error": {"message": ...}}, the shape both the
+// Gemini and OpenAI APIs use, falling back to the status line
+func errorMessage(response *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 64*1024))
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+		return parsed.Error.Message
+	}
+	return response.Status
+}
+
+// readEvents calls handle with the data of each server-sent event until
+// the body ends or handle reports done
+func readEvents(body io.Reader, handle func(data string) (done bool, err error)) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+			continue
+		}
+		if line != "" || len(data) == 0 {
+			continue
+		}
+		// A blank line ends the event
+		done, err := handle(strings.Join(data, "\n"))
+		if err != nil || done {
+			return err
+		}
+		data = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		_, err := handle(strings.Join(data, "\n"))
+		return err
+	}
+	return nil
+}
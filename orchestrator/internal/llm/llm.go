@@ -0,0 +1,98 @@
+// Package llm is the orchestrator's interface to language models. The
+// backend is chosen with LLM_BACKEND in .gemini-config: the gemini CLI the
+// zsh scripts use, the Gemini HTTP API, an OpenAI-compatible server such as
+// a local Ollama, or a deterministic fake for working offline.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gemini-orchestrator/internal/config"
+)
+
+// Timeout for a single request; generation can take a while
+const Timeout = 2 * time.Minute
+
+// Request is a prompt with extra input such as a diff or a conversation,
+// the way the scripts pass --prompt and standard input to gemini
+type Request struct {
+	Model  string // Empty uses the backend's default
+	Prompt string
+	Input  string
+}
+
+// text combines input and prompt into one message, in the order the
+// gemini CLI appends --prompt to standard input
+func (r Request) text() string {
+	if r.Input == "" {
+		return r.Prompt
+	}
+	return r.Input + "\n\n" + r.Prompt
+}
+
+// Backend generates text from a model
+type Backend interface {
+	// Name identifies the backend, as written in LLM_BACKEND
+	Name() string
+	// Generate returns the complete, cleaned response
+	Generate(ctx context.Context, req Request) (string, error)
+	// Stream starts a request whose response is delivered in chunks
+	Stream(req Request) *Stream
+	// Models lists the models the backend offers
+	Models(ctx context.Context) ([]string, error)
+	// EstimateTokens approximates how many tokens text uses
+	EstimateTokens(text string) int
+}
+
+// New returns the backend selected by the configuration, checking that it
+// can be used before any request is made
+func New(c *config.Config) (Backend, error) {
+	endpoint := c.LLMEndpoint
+	switch c.LLMBackend {
+	case "", "gemini-cli":
+		if _, err := exec.LookPath("gemini"); err != nil {
+			return nil, fmt.Errorf("gemini not found on PATH - run /doctor for details")
+		}
+		return &GeminiCLI{}, nil
+	case "gemini-api":
+		key := os.Getenv("GEMINI_API_KEY")
+		if key == "" {
+			key = os.Getenv("GOOGLE_API_KEY")
+		}
+		if key == "" {
+			return nil, fmt.Errorf("LLM_BACKEND=gemini-api needs GEMINI_API_KEY in the environment")
+		}
+		if endpoint == "" {
+			endpoint = defaultGeminiEndpoint
+		}
+		return &GeminiAPI{Endpoint: strings.TrimSuffix(endpoint, "/"), Key: key, Client: http.DefaultClient}, nil
+	case "openai":
+		if endpoint == "" {
+			endpoint = defaultOpenAIEndpoint
+		}
+		return &OpenAI{Endpoint: strings.TrimSuffix(endpoint, "/"), Key: os.Getenv("OPENAI_API_KEY"), Client: http.DefaultClient}, nil
+	case "fake":
+		// LLM_ENDPOINT may name a file holding the reply to every request
+		if endpoint == "" {
+			return &Fake{}, nil
+		}
+		reply, err := os.ReadFile(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("fake LLM reply: %w", err)
+		}
+		return &Fake{Reply: string(reply)}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_BACKEND %q (use one of %s)", c.LLMBackend, strings.Join(config.LLMBackends, ", "))
+	}
+}
+
+// estimateTokens is the usual rule of thumb of four bytes per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
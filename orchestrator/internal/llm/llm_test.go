@@ -0,0 +1,210 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/sanitize"
+)
+
+func TestNewSelectsBackend(t *testing.T) {
+	reply := filepath.Join(t.TempDir(), "reply")
+	if err := os.WriteFile(reply, []byte("canned"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	tests := []struct {
+		backend, endpoint string
+		name              string // Expected Name(), empty when New fails
+		err               string
+	}{
+		{backend: "fake", name: "fake"},
+		{backend: "fake", endpoint: reply, name: "fake"},
+		{backend: "fake", endpoint: filepath.Join(t.TempDir(), "missing"), err: "fake LLM reply"},
+		{backend: "openai", name: "openai"},
+		{backend: "gemini-api", err: "GEMINI_API_KEY"},
+		{backend: "claude", err: `unknown LLM_BACKEND "claude"`},
+	}
+	for _, test := range tests {
+		backend, err := New(&config.Config{LLMBackend: test.backend, LLMEndpoint: test.endpoint})
+		switch {
+		case test.err != "":
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("New(%s, %q): expected error containing %q, got %v", test.backend, test.endpoint, test.err, err)
+			}
+		case err != nil:
+			t.Errorf("New(%s, %q): %v", test.backend, test.endpoint, err)
+		case backend.Name() != test.name:
+			t.Errorf("New(%s, %q): got backend %s", test.backend, test.endpoint, backend.Name())
+		}
+	}
+
+	backend, err := New(&config.Config{LLMBackend: "fake", LLMEndpoint: reply})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := backend.(*Fake).Reply; got != "canned" {
+		t.Errorf("fake reply from LLM_ENDPOINT: got %q", got)
+	}
+	backend, _ = New(&config.Config{LLMBackend: "openai"})
+	if got := backend.(*OpenAI).Endpoint; got != defaultOpenAIEndpoint {
+		t.Errorf("openai default endpoint: got %q", got)
+	}
+	t.Setenv("GOOGLE_API_KEY", "key")
+	if backend, err = New(&config.Config{LLMBackend: "gemini-api"}); err != nil || backend.(*GeminiAPI).Key != "key" {
+		t.Errorf("gemini-api should fall back to GOOGLE_API_KEY, got %v", err)
+	}
+}
+
+// drain runs a stream the way the Bubble Tea loop does, returning the
+// chunks and the final error
+func drain(t *testing.T, s *Stream) ([]string, error) {
+	t.Helper()
+	var chunks []string
+	cmd := s.Next()
+	for i := 0; i < 10000; i++ {
+		switch msg := cmd().(type) {
+		case ChunkMsg:
+			if msg.Stream != s {
+				t.Fatal("chunk from another stream")
+			}
+			chunks = append(chunks, msg.Text)
+			cmd = s.Next()
+		case DoneMsg:
+			if msg.Stream != s {
+				t.Fatal("done from another stream")
+			}
+			return chunks, msg.Err
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+	t.Fatal("stream did not end")
+	return nil, nil
+}
+
+func TestFakeStream(t *testing.T) {
+	backend := &Fake{Reply: "Loaded cached credentials.\nfeat: add the thing\n\n(node:42) [DEP0040] DeprecationWarning: punycode\nbody line"}
+	chunks, err := drain(t, backend.Stream(Request{Prompt: "write"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(chunks, ""); got != "feat: add the thing\n\nbody line" {
+		t.Errorf("streamed text: got %q", got)
+	}
+	if len(chunks) < 2 {
+		t.Errorf("expected the reply in several chunks, got %q", chunks)
+	}
+
+	text, err := backend.Generate(context.Background(), Request{Prompt: "write"})
+	if err != nil || text != "feat: add the thing\n\nbody line" {
+		t.Errorf("Generate: got %q, %v", text, err)
+	}
+
+	// Answers that merely start like a notice stream unchanged
+	chunks, err = drain(t, (&Fake{Reply: "Cached results are now invalidated\n\nbody"}).Stream(Request{}))
+	if got := strings.Join(chunks, ""); err != nil || got != "Cached results are now invalidated\n\nbody" {
+		t.Errorf("subject starting with Cached: got %q, %v", got, err)
+	}
+
+	text, _ = (&Fake{}).Generate(context.Background(), Request{Prompt: "first line\nsecond", Input: "12345"})
+	if text != `Fake response to "first line" with 5 bytes of input.` {
+		t.Errorf("default fake reply: got %q", text)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	_, err := drain(t, (&Fake{Reply: "Loaded cached credentials.\n"}).Stream(Request{}))
+	if !errors.Is(err, sanitize.ErrEmpty) {
+		t.Errorf("only noise: expected ErrEmpty, got %v", err)
+	}
+
+	failing := startStream(func(ctx context.Context, send func(string)) error {
+		send("partial ")
+		return fmt.Errorf("boom")
+	})
+	chunks, err := drain(t, failing)
+	if err == nil || err.Error() != "boom" || strings.Join(chunks, "") != "partial " {
+		t.Errorf("failing producer: got %q, %v", chunks, err)
+	}
+
+	started := make(chan struct{})
+	blocked := startStream(func(ctx context.Context, send func(string)) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+	blocked.Cancel()
+	if _, err := drain(t, blocked); !errors.Is(err, ErrCancelled) {
+		t.Errorf("cancelled stream: expected ErrCancelled, got %v", err)
+	}
+}
+
+func TestOpenAIStream(t *testing.T) {
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			fmt.Fprint(w, `{"data": [{"id": "llama3.2"}, {"id": "qwen2.5"}]}`)
+		case "/v1/chat/completions":
+			var body struct {
+				Model    string `json:"model"`
+				Messages []struct {
+					Content string `json:"content"`
+				} `json:"messages"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			models = append(models, body.Model)
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, word := range []string{"Hello", " from ", body.Messages[0].Content} {
+				data, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"delta": map[string]string{"content": word}}}})
+				fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	backend := &OpenAI{Endpoint: server.URL + "/v1", Key: "secret", Client: server.Client()}
+	text, err := backend.Generate(context.Background(), Request{Model: "qwen2.5", Prompt: "the prompt", Input: "input"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Hello from input\n\nthe prompt" {
+		t.Errorf("response: got %q", text)
+	}
+
+	// Without a model the server's first one is used, never a Gemini name
+	if _, err := drain(t, backend.Stream(Request{Prompt: "hi"})); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(models, ",") != "qwen2.5,llama3.2" {
+		t.Errorf("models sent: got %q", models)
+	}
+
+	backend.Key = ""
+	if _, err := drain(t, backend.Stream(Request{Model: "qwen2.5", Prompt: "hi"})); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the server's error, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// defaultOpenAIEndpoint is Ollama's OpenAI-compatible API
+const defaultOpenAIEndpoint = "http://localhost:11434/v1"
+
+// OpenAI talks to any server implementing OpenAI's chat completions API,
+// typically a local model server. Without a model in the request it uses
+// the first one the server lists.
+type OpenAI struct {
+	Endpoint string // Base URL ending in the API version, e.g. .../v1
+	Key      string // Sent as a bearer token when set
+	Client   *http.Client
+}
+
+func (b *OpenAI) Name() string { return "openai" }
+
+func (b *OpenAI) Generate(ctx context.Context, req Request) (string, error) {
+	return collect(ctx, b.Stream(req))
+}
+
+func (b *OpenAI) Stream(req Request) *Stream {
+	return startStream(func(ctx context.Context, send func(string)) error {
+		model := req.Model
+		if model == "" {
+			names, err := b.Models(ctx)
+			if err != nil {
+				return err
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("%s offers no models, set LLM_MODEL", b.Endpoint)
+			}
+			model = names[0]
+		}
+		body := map[string]any{
+			"model":    model,
+			"stream":   true,
+			"messages": []map[string]string{{"role": "user", "content": req.text()}},
+		}
+		response, err := postJSON(ctx, b.Client, b.Endpoint+"/chat/completions", b.headers(), body)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		return readEvents(response.Body, func(data string) (bool, error) {
+			if data == "[DONE]" {
+				return true, nil
+			}
+			var chunk struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return false, fmt.Errorf("unexpected response from %s: %w", b.Endpoint, err)
+			}
+			for _, choice := range chunk.Choices {
+				send(choice.Delta.Content)
+			}
+			return false, nil
+		})
+	})
+}
+
+func (b *OpenAI) Models(ctx context.Context) ([]string, error) {
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, b.Client, b.Endpoint+"/models", b.headers(), &list); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(list.Data))
+	for _, model := range list.Data {
+		names = append(names, model.ID)
+	}
+	return names, nil
+}
+
+func (b *OpenAI) EstimateTokens(text string) int {
+	return estimateTokens(text)
+}
+
+func (b *OpenAI) headers() map[string]string {
+	if b.Key == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + b.Key}
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/sanitize"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCancelled ends a stream stopped with Cancel
+var ErrCancelled = errors.New("cancelled")
+
+// Stream is a response delivered while it is generated. Chunks go through
+// the Bubble Tea loop with Next, so the UI stays responsive meanwhile.
+type Stream struct {
+	chunks chan string
+	cancel context.CancelFunc
+	err    error // Set before chunks is closed
+}
+
+// ChunkMsg carries the next part of a streamed response
+type ChunkMsg struct {
+	Stream *Stream
+	Text   string
+}
+
+// DoneMsg is sent once a stream has ended; Err is ErrCancelled after Cancel
+type DoneMsg struct {
+	Stream *Stream
+	Err    error
+}
+
+// produceFunc generates a response, passing each piece to send as it
+// arrives, until done or ctx is cancelled
+type produceFunc func(ctx context.Context, send func(string)) error
+
+// startStream runs produce in the background. CLI noise is removed from
+// its output as it passes through, see noticeFilter.
+func startStream(produce produceFunc) *Stream {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	s := &Stream{chunks: make(chan string, 16), cancel: cancel}
+
+	go func() {
+		defer close(s.chunks)
+		defer cancel()
+
+		var filter noticeFilter
+		send := func(text string) {
+			if text = filter.write(text); text == "" {
+				return
+			}
+			select {
+			case s.chunks <- text:
+			case <-ctx.Done():
+			}
+		}
+
+		err := produce(ctx, send)
+		// Complete a held back last line so it is checked and passed on
+		if filter.pending() {
+			send("\n")
+		}
+
+		switch {
+		case errors.Is(ctx.Err(), context.Canceled):
+			s.err = ErrCancelled
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			s.err = fmt.Errorf("no answer within %s", Timeout)
+		case err != nil:
+			s.err = err
+		case !filter.started:
+			s.err = sanitize.ErrEmpty
+		}
+	}()
+	return s
+}
+
+// Next waits for the next chunk, or a DoneMsg when the stream has ended;
+// receivers call it again after each ChunkMsg
+func (s *Stream) Next() tea.Cmd {
+	return func() tea.Msg {
+		text, ok := <-s.chunks
+		if !ok {
+			return DoneMsg{Stream: s, Err: s.err}
+		}
+		return ChunkMsg{Stream: s, Text: text}
+	}
+}
+
+// Cancel stops the request; output read so far has already been
+// delivered or is dropped
+func (s *Stream) Cancel() {
+	s.cancel()
+}
+
+// collect waits for the whole response of a stream
+func collect(ctx context.Context, s *Stream) (string, error) {
+	var response strings.Builder
+	for {
+		select {
+		case text, ok := <-s.chunks:
+			if !ok {
+				if s.err != nil {
+					return "", s.err
+				}
+				return sanitize.Clean(response.String()), nil
+			}
+			response.WriteString(text)
+		case <-ctx.Done():
+			s.Cancel()
+			return "", ctx.Err()
+		}
+	}
+}
@@ -1,6 +1,16 @@
 package models
 
 import (
+	"fmt"
+
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/control"
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/plugins"
+	"gemini-orchestrator/internal/script"
+
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -21,6 +31,27 @@ type Model struct {
 	IsBuilding         bool
 	ShowExitConfirm    bool
 	ZshMode            bool
+	Script             *script.Run // Batch script currently running, nil when idle
+	Confirm            *Confirm    // Pending yes/no question, nil when none
+	Control            *control.Server
+	ContextFiles       []string // Files added with "open" requests on the control socket
+	Plugins            []plugins.Plugin
+	Config             *config.Config      // Effective .gemini-config, as the scripts see it
+	Overrides          config.Overrides    // Session /model and /dryrun settings passed to launched scripts
+	Panel              Panel               // Full-screen view currently open, nil for the conversation
+	Git                *git.Status         // Repository state for the status bar, nil outside a repository
+	PullRequest        *github.PullRequest // Open pull request for PullRequestBranch, nil when none
+	PullRequestBranch  string              // Branch PullRequest was looked up for
+	Watcher            *git.Watcher        // Polls the repository for outside changes, nil outside a repository
+	Chat               []ChatTurn          // Conversation with Gemini this session
+	ChatStream         *llm.Stream         // Answer being streamed, nil when idle
+	ChatReply          string              // Part of the answer received so far
+}
+
+// Confirm is a yes/no question shown in place of the help prompt
+type Confirm struct {
+	Prompt   string
+	OnAnswer func(yes bool, m *Model) tea.Cmd
 }
 
 func InitialModel() Model {
@@ -32,13 +63,20 @@ func InitialModel() Model {
 	ti.Cursor.SetMode(cursor.CursorStatic)
 	ti.Prompt = "> " // Default prompt
 
+	messages := []string{}
+	cfg, err := config.Load(config.DefaultPaths())
+	if err != nil {
+		messages = append(messages, fmt.Sprintf("⚠️  Failed to load configuration, using defaults: %v", err))
+		cfg, _ = config.Load(config.Paths{})
+	}
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 	return Model{
 		TextInput:          ti,
-		Messages:           []string{},
+		Messages:           messages,
 		Suggestions:        []string{},
 		SelectedSuggestion: 0,
 		ShowSuggestions:    false,
@@ -49,6 +87,9 @@ func InitialModel() Model {
 		IsBuilding:         false,
 		ShowExitConfirm:    false,
 		ZshMode:            false,
+		Plugins:            plugins.Discover(SlashCommands),
+		Config:             cfg,
+		Overrides:          config.OverridesFromEnv(),
 	}
 }
 
@@ -69,6 +110,20 @@ func (m *Model) UpdatePromptForZshMode() {
 	}
 }
 
+// AddResult attaches an output line to the most recent history entry
+func (m *Model) AddResult(result string) {
+	if len(m.Messages) == 0 {
+		m.Messages = append(m.Messages, result)
+		return
+	}
+	m.Messages[len(m.Messages)-1] += "\n  ⎿  " + result
+}
+
+// Publish notifies control socket subscribers, if the socket is running
+func (m *Model) Publish(event string, data map[string]string) {
+	m.Control.Publish(event, data)
+}
+
 func slicesEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -80,4 +135,3 @@ func slicesEqual(a, b []string) bool {
 	}
 	return true
 }
-
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"os"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/repocontext"
+)
+
+// ChatTurn is one side of the conversation with Gemini
+type ChatTurn struct {
+	Role string // "user" or "model"
+	Text string
+}
+
+// LLM returns the configured backend and the model to ask
+func (m *Model) LLM() (llm.Backend, string, error) {
+	backend, err := llm.New(m.Config)
+	model, _ := m.Config.NativeModel(m.Overrides)
+	return backend, model, err
+}
+
+// RepoContext loads the GEMINI.md context for the working directory and
+// for paths, counting tokens the way the configured backend does
+func (m *Model) RepoContext(paths ...string) (*repocontext.Context, error) {
+	opts := repocontext.Options{Paths: paths}
+	if m.Git != nil {
+		opts.Root = m.Git.Root
+	} else if root, err := git.Root(); err == nil {
+		opts.Root = root
+	}
+	if dir, err := os.Getwd(); err == nil {
+		opts.Dir = dir
+	}
+	if backend, err := llm.New(m.Config); err == nil {
+		opts.Estimate = backend.EstimateTokens
+	}
+	return repocontext.Load(opts)
+}
+
+// AddResponse attaches a multi-line answer to the most recent history entry,
+// indenting continuation lines under the first
+func (m *Model) AddResponse(text string) {
+	m.AddResult(IndentResponse(text))
+}
+
+// IndentResponse aligns the lines after the first with a result's text
+func IndentResponse(text string) string {
+	return strings.ReplaceAll(text, "\n", "\n     ")
+}
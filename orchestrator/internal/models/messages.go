@@ -6,6 +6,9 @@ import (
 	"syscall"
 	"time"
 
+	"gemini-orchestrator/internal/doctor"
+	"gemini-orchestrator/internal/github"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -14,6 +17,67 @@ type BuildErrorMsg struct{ Err error }
 type ShutdownMsg struct{ Signal os.Signal }
 type CtrlCTimeoutMsg struct{}
 
+// ExecFinishedMsg is sent when a command run through tea.ExecProcess returns
+type ExecFinishedMsg struct {
+	Command   string
+	Err       error
+	NewCommit *CommitInfo // Set when the command created a commit
+}
+
+// CommandFailedMsg is sent when a command fails without running a process
+type CommandFailedMsg struct{ Err error }
+
+// CommitInfo identifies a commit for display and events
+type CommitInfo struct {
+	SHA     string
+	Subject string
+}
+
+// PluginOutputMsg carries the captured output of a non-interactive plugin
+type PluginOutputMsg struct {
+	Name   string
+	Output string
+	Err    error
+}
+
+// DoctorMsg carries environment check results
+type DoctorMsg struct {
+	Results []doctor.Result
+	Startup bool // Checked at launch rather than by /doctor
+}
+
+// StartIssueMsg carries the issue fetched by /start
+type StartIssueMsg struct {
+	Issue  *github.Issue
+	Self   string // Login gh is authenticated as, empty when unknown
+	Assign string // "yes" or "no" from --assign/--no-assign, empty to ask
+	Err    error
+}
+
+// IssueAssignedMsg is sent when /start assigned an issue to the user
+type IssueAssignedMsg struct {
+	Number int
+	Err    error
+}
+
+// FinishMsg reports the cleanup /finish did after a merge; Steps holds
+// what was done before any error
+type FinishMsg struct {
+	Branch string
+	Steps  []string
+	Err    error
+}
+
+// ModelListMsg carries the models offered by the LLM backend
+type ModelListMsg struct {
+	Backend string
+	Models  []string
+	Err     error
+}
+
+// RunScriptMsg starts a batch script, used when one is given on the command line
+type RunScriptMsg struct{ Args []string }
+
 func CtrlCTimeoutCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(time.Time) tea.Msg {
 		return CtrlCTimeoutMsg{}
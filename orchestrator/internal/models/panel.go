@@ -0,0 +1,12 @@
+package models
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// Panel is a full-screen view, such as the /config editor, that replaces the
+// history and input bar and receives all keys (except Ctrl+C) while open
+type Panel interface {
+	// Update handles a message; a panel closes itself by setting m.Panel to nil
+	Update(msg tea.Msg, m *Model) tea.Cmd
+	// View renders the panel using the model's current size
+	View(m Model) string
+}
@@ -6,11 +6,70 @@ var SlashCommands = []string{
 	"/commit",
 	"/pr",
 	"/issue",
+	"/status",
+	"/stage",
+	"/diff",
+	"/log",
+	"/branch",
+	"/start",
+	"/finish",
+	"/context",
+	"/run",
+	"/plugins",
+	"/doctor",
+	"/config",
+	"/model",
+	"/dryrun",
 	"/help",
 	"/clear",
 	"/reload",
 }
 
+// CommandDescriptions are shown next to built-in commands in the suggestions
+var CommandDescriptions = map[string]string{
+	"/commit":  "Generate and review a commit message ([context]|--zsh)",
+	"/pr":      "Create or update a pull request ([context]|--zsh)",
+	"/issue":   "Create, edit, comment on or close issues ([request]|--zsh)",
+	"/status":  "Review and stage changes file by file",
+	"/stage":   "Stage hunks and lines interactively ([--staged] [path...])",
+	"/diff":    "Browse a diff with syntax highlighting ([--staged] [path|rev])",
+	"/log":     "Browse commits and explain them with Gemini ([range] [path...])",
+	"/branch":  "List, switch, create, rename and delete branches ([description])",
+	"/start":   "Create a branch for an issue and link it to /commit and /pr (<issue>)",
+	"/finish":  "Clean up after the branch's pull request merged",
+	"/context": "Show the GEMINI.md context sent to Gemini ([path...])",
+	"/run":     "Run a batch script of commands",
+	"/plugins": "Rescan and list plugin commands",
+	"/doctor":  "Check dependencies, GitHub login and configuration",
+	"/config":  "Show and edit configuration with its source files",
+	"/model":   "Use a different model for this session ([name|list|reset])",
+	"/dryrun":  "Run scripts in dry-run mode for this session (on|off)",
+	"/clear":   "Clear the conversation history",
+	"/reload":  "Rebuild the orchestrator",
+}
+
+// AvailableCommands returns the built-in commands followed by plugin commands
+func (m *Model) AvailableCommands() []string {
+	available := append([]string{}, SlashCommands...)
+	for _, p := range m.Plugins {
+		available = append(available, p.Command())
+	}
+	return available
+}
+
+// CommandDescription returns the description and argument hint for a command
+func (m *Model) CommandDescription(command string) string {
+	for _, p := range m.Plugins {
+		if p.Command() == command {
+			if p.Args != "" {
+				return strings.TrimSpace(p.Args + "  " + p.Description)
+			}
+			return p.Description
+		}
+	}
+	return CommandDescriptions[command]
+}
+
 func (m *Model) UpdateSuggestions() {
 	input := m.TextInput.Value()
 
@@ -20,7 +79,7 @@ func (m *Model) UpdateSuggestions() {
 		m.UpdatePromptForZshMode()
 		oldSuggestions := m.Suggestions
 		m.Suggestions = []string{}
-		for _, cmd := range SlashCommands {
+		for _, cmd := range m.AvailableCommands() {
 			if strings.HasPrefix(cmd, input) {
 				m.Suggestions = append(m.Suggestions, cmd)
 			}
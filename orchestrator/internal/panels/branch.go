@@ -0,0 +1,490 @@
+package panels
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/sanitize"
+	"gemini-orchestrator/internal/ui"
+	"gemini-orchestrator/internal/utils"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// suggestBranchPrompt asks Gemini for a branch name, like auto-commit does
+const suggestBranchPrompt = "Suggest a git branch name for the work described on standard input. " +
+	"Use one of the categories feat, fix, docs or refactor, a slash, then a few lowercase words joined by hyphens, e.g. 'feat/user-login' or 'fix/memory-leak'. " +
+	"Output ONLY the branch name, no explanations or code blocks."
+
+// maxSuggestInput bounds the diff sent along with the description
+const maxSuggestInput = 20000
+
+type branchMode int
+
+const (
+	branchList branchMode = iota
+	branchCreate
+	branchRename
+	branchConfirmDelete
+)
+
+// branchesLoadedMsg carries the reloaded branch list
+type branchesLoadedMsg struct {
+	branches []git.Branch
+	err      error
+}
+
+// branchActionMsg reports a finished switch, create, rename or delete
+type branchActionMsg struct {
+	message string
+	err     error
+}
+
+// branchUnmergedMsg reports the work a deletion would lose
+type branchUnmergedMsg struct {
+	branch git.Branch
+	count  int
+	err    error
+}
+
+// branchSuggestionMsg carries a branch name suggested by Gemini
+type branchSuggestionMsg struct {
+	name string
+	err  error
+}
+
+// BranchPanel lists local branches to switch to, create, rename or delete.
+// New branches are named from a description with the configured prefix
+// and naming style, or suggested by Gemini. In dry-run mode every change
+// is only reported.
+type BranchPanel struct {
+	root       string
+	dryRun     bool
+	branches   []git.Branch
+	selected   int
+	mode       branchMode
+	input      textinput.Model
+	branchType int         // Index into config.BranchTypes for new branches
+	renaming   string      // Branch being renamed
+	deleting   *git.Branch // Branch awaiting confirmation to delete
+	unmerged   int         // Commits deleting it would lose
+	suggesting bool
+	message    string
+	err        string
+}
+
+// NewBranchPanel lists branches, the result of git.Branches in root. A
+// description opens the new branch form with it filled in.
+func NewBranchPanel(root string, branches []git.Branch, description string, dryRun bool) *BranchPanel {
+	p := &BranchPanel{root: root, branches: branches, dryRun: dryRun}
+	p.input = textinput.New()
+	p.input.Prompt = ""
+	p.input.CharLimit = 120
+	p.input.Cursor.SetMode(cursor.CursorStatic)
+	for i, b := range branches {
+		if b.Current {
+			p.selected = i
+		}
+	}
+	if description != "" {
+		p.startCreate()
+		p.input.SetValue(description)
+	}
+	return p
+}
+
+// Load lists the branches again
+func (p *BranchPanel) Load() tea.Cmd {
+	root := p.root
+	return func() tea.Msg {
+		branches, err := git.Branches(root)
+		return branchesLoadedMsg{branches: branches, err: err}
+	}
+}
+
+func (p *BranchPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case branchesLoadedMsg:
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		p.branches = msg.branches
+		p.selected = max(0, min(p.selected, len(p.branches)-1))
+		return nil
+	case branchActionMsg:
+		p.message, p.err = msg.message, ""
+		if msg.err != nil {
+			p.message, p.err = "", msg.err.Error()
+			return nil
+		}
+		p.mode = branchList
+		return tea.Batch(p.Load(), git.RefreshStatus())
+	case branchUnmergedMsg:
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		if msg.count == 0 {
+			// Nothing would be lost, even if git -d disagrees about where it was merged
+			return p.delete(msg.branch.Name, true)
+		}
+		p.mode, p.deleting, p.unmerged = branchConfirmDelete, &msg.branch, msg.count
+		return nil
+	case branchSuggestionMsg:
+		p.suggesting = false
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		p.applySuggestion(msg.name)
+		return nil
+	case tea.KeyMsg:
+		switch p.mode {
+		case branchCreate:
+			return p.updateCreate(msg, m)
+		case branchRename:
+			return p.updateRename(msg)
+		case branchConfirmDelete:
+			return p.updateConfirm(msg)
+		}
+		return p.updateList(msg, m)
+	}
+	return nil
+}
+
+func (p *BranchPanel) updateList(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		p.selected = max(0, p.selected-1)
+	case "down", "j":
+		p.selected = max(0, min(len(p.branches)-1, p.selected+1))
+	case "enter":
+		if branch := p.selectedBranch(); branch != nil {
+			if branch.Current {
+				p.message, p.err = "Already on "+branch.Name, ""
+				return nil
+			}
+			name := branch.Name
+			return p.action("switch to "+name, "Switched to "+name, func() error { return git.SwitchBranch(p.root, name) })
+		}
+	case "n":
+		p.startCreate()
+	case "m":
+		if branch := p.selectedBranch(); branch != nil {
+			p.mode, p.renaming = branchRename, branch.Name
+			p.message, p.err = "", ""
+			p.input.SetValue(branch.Name)
+			p.input.CursorEnd()
+			p.input.Focus()
+		}
+	case "d":
+		if branch := p.selectedBranch(); branch != nil {
+			if branch.Current {
+				p.message, p.err = "", "Switch to another branch before deleting "+branch.Name
+				return nil
+			}
+			root, selected := p.root, *branch
+			return func() tea.Msg {
+				count, err := git.UnmergedCommits(root, selected)
+				return branchUnmergedMsg{branch: selected, count: count, err: err}
+			}
+		}
+	case "r":
+		return p.Load()
+	case "esc", "q":
+		m.Panel = nil
+	}
+	return nil
+}
+
+func (p *BranchPanel) startCreate() {
+	p.mode = branchCreate
+	p.message, p.err = "", ""
+	p.input.SetValue("")
+	p.input.Placeholder = "describe the work, e.g. retry uploads on timeout"
+	p.input.Focus()
+}
+
+func (p *BranchPanel) updateCreate(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "tab":
+		p.branchType = (p.branchType + 1) % len(config.BranchTypes)
+		return nil
+	case "shift+tab":
+		p.branchType = (p.branchType + len(config.BranchTypes) - 1) % len(config.BranchTypes)
+		return nil
+	case "ctrl+g":
+		return p.suggest(m)
+	case "enter":
+		name := p.newBranchName(m)
+		if err := git.ValidateBranchName(name); err != nil {
+			p.err = err.Error()
+			return nil
+		}
+		return p.action("create and switch to "+name, "Created and switched to "+name, func() error { return git.CreateBranch(p.root, name) })
+	case "esc":
+		p.mode = branchList
+		p.err = ""
+		p.input.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.err = ""
+	return cmd
+}
+
+func (p *BranchPanel) updateRename(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		oldName, newName := p.renaming, strings.TrimSpace(p.input.Value())
+		if newName == oldName {
+			p.mode = branchList
+			return nil
+		}
+		if err := git.ValidateBranchName(newName); err != nil {
+			p.err = err.Error()
+			return nil
+		}
+		return p.action(fmt.Sprintf("rename %s to %s", oldName, newName), fmt.Sprintf("Renamed %s to %s", oldName, newName), func() error {
+			return git.RenameBranch(p.root, oldName, newName)
+		})
+	case "esc":
+		p.mode = branchList
+		p.err = ""
+		p.input.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.err = ""
+	return cmd
+}
+
+func (p *BranchPanel) updateConfirm(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		p.mode = branchList
+		return p.delete(p.deleting.Name, true)
+	case "n", "N", "esc":
+		p.mode, p.deleting = branchList, nil
+		p.message = "Kept the branch"
+	}
+	return nil
+}
+
+func (p *BranchPanel) delete(name string, force bool) tea.Cmd {
+	return p.action("delete "+name, "Deleted "+name, func() error { return git.DeleteBranch(p.root, name, force) })
+}
+
+// action runs a change in the background and reports done when it
+// succeeds; a dry run only reports what it would do
+func (p *BranchPanel) action(would, done string, run func() error) tea.Cmd {
+	if p.dryRun {
+		return func() tea.Msg {
+			return branchActionMsg{message: "🔍 Dry run: would " + would}
+		}
+	}
+	return func() tea.Msg {
+		return branchActionMsg{message: done, err: run()}
+	}
+}
+
+func (p *BranchPanel) selectedBranch() *git.Branch {
+	if p.selected < 0 || p.selected >= len(p.branches) {
+		return nil
+	}
+	return &p.branches[p.selected]
+}
+
+// newBranchName applies the configured prefix and naming style to the
+// description typed so far
+func (p *BranchPanel) newBranchName(m *models.Model) string {
+	return m.Config.BranchName(config.BranchTypes[p.branchType], p.input.Value())
+}
+
+// suggest asks Gemini for a branch name from the description and the
+// staged changes, or the unstaged ones when nothing is staged
+func (p *BranchPanel) suggest(m *models.Model) tea.Cmd {
+	if p.suggesting {
+		return nil
+	}
+	backend, model, err := m.LLM()
+	if err != nil {
+		p.err = err.Error()
+		return nil
+	}
+	p.suggesting, p.err = true, ""
+
+	root, description := p.root, strings.TrimSpace(p.input.Value())
+	return func() tea.Msg {
+		changes, err := git.DiffText(root, git.DiffOptions{Staged: true})
+		if err == nil && changes == "" {
+			changes, err = git.DiffText(root, git.DiffOptions{})
+		}
+		if err != nil {
+			return branchSuggestionMsg{err: err}
+		}
+		if description == "" && changes == "" {
+			return branchSuggestionMsg{err: fmt.Errorf("describe the work or make some changes first")}
+		}
+		if len(changes) > maxSuggestInput {
+			changes = utils.TruncateBytes(changes, maxSuggestInput) + "\n[diff truncated]\n"
+		}
+
+		input := "Description: " + description + "\n\nChanges:\n" + changes
+		output, err := backend.Generate(context.Background(), llm.Request{Model: model, Prompt: suggestBranchPrompt, Input: input})
+		if err != nil {
+			return branchSuggestionMsg{err: err}
+		}
+		name, err := sanitize.Line(output)
+		return branchSuggestionMsg{name: name, err: err}
+	}
+}
+
+// applySuggestion turns "fix/memory-leak" into the fix type and the
+// description "memory leak", so the configured prefix and style apply
+func (p *BranchPanel) applySuggestion(name string) {
+	kind, rest, found := strings.Cut(name, "/")
+	if !found {
+		rest = name
+	} else if index := slices.Index(config.BranchTypes, kind); index >= 0 {
+		p.branchType = index
+	}
+	p.input.SetValue(strings.NewReplacer("-", " ", "_", " ", "/", " ").Replace(rest))
+	p.input.CursorEnd()
+	p.message = "Suggested by Gemini: " + name
+}
+
+func (p *BranchPanel) View(m models.Model) string {
+	switch p.mode {
+	case branchCreate:
+		return p.viewCreate(m)
+	case branchRename:
+		return p.viewRename()
+	}
+
+	details := ""
+	if p.dryRun {
+		details = "  dry run"
+	}
+	var view string
+	view += ui.SuggestionStyle.Render(fmt.Sprintf("Branches (%d)", len(p.branches))+ui.BlurredStyle.Render(details)) + "\n\n"
+
+	nameWidth := 0
+	for _, b := range p.branches {
+		nameWidth = max(nameWidth, len(b.Name))
+	}
+	nameWidth = min(nameWidth, 40)
+
+	now := time.Now()
+	start, end := visibleRange(p.selected, len(p.branches), listHeight(m.Height, 12))
+	for i := start; i < end; i++ {
+		b := p.branches[i]
+		marker := "  "
+		if b.Current {
+			marker = "* "
+		}
+		name := truncate(b.Name, nameWidth)
+		name += strings.Repeat(" ", nameWidth-len([]rune(name)))
+
+		var tracking []string
+		if b.Ahead > 0 {
+			tracking = append(tracking, fmt.Sprintf("↑%d", b.Ahead))
+		}
+		if b.Behind > 0 {
+			tracking = append(tracking, fmt.Sprintf("↓%d", b.Behind))
+		}
+		if b.Gone {
+			tracking = append(tracking, "upstream gone")
+		} else if b.Upstream == "" {
+			tracking = append(tracking, "local only")
+		}
+		details := fmt.Sprintf("  %s %s, %s", b.Hash, truncate(b.Subject, max(10, m.Width-nameWidth-50)), relativeTime(b.Date, now))
+
+		style := ui.SuggestionStyle
+		if i == p.selected {
+			style = ui.SelectedSuggestionStyle
+		}
+		view += style.Render(marker+name) + ui.StatusBarDirtyStyle.Render(" "+strings.Join(tracking, " ")) + ui.BlurredStyle.Render(details) + "\n"
+	}
+	view += "\n"
+
+	if p.mode == branchConfirmDelete && p.deleting != nil {
+		view += ui.ConfirmStyle.MarginTop(0).Render(fmt.Sprintf("%s has %d commit(s) not merged into HEAD or its upstream. Delete anyway? (y/n)",
+			p.deleting.Name, p.unmerged)) + "\n"
+		return view
+	}
+	view += p.status()
+	view += keyHints("↑/↓ select", "enter switch", "n new", "m rename", "d delete", "r reload", "esc close")
+	return view
+}
+
+func (p *BranchPanel) viewCreate(m models.Model) string {
+	var view string
+	view += ui.SuggestionStyle.Render("New branch from HEAD") + "\n\n"
+
+	var types []string
+	for i, kind := range config.BranchTypes {
+		if i == p.branchType {
+			types = append(types, ui.SelectedSuggestionStyle.Padding(0).Render("‹"+kind+"›"))
+		} else {
+			types = append(types, ui.BlurredStyle.Render(" "+kind+" "))
+		}
+	}
+	view += ui.SuggestionStyle.Render("Type:        ") + strings.Join(types, " ") + "\n"
+	view += ui.SuggestionStyle.Render("Description: ") + p.input.View() + "\n"
+
+	name := p.newBranchName(&m)
+	switch err := git.ValidateBranchName(name); {
+	case strings.TrimSpace(p.input.Value()) == "":
+		view += ui.SuggestionStyle.Render("Name:        ") + ui.BlurredStyle.Render(m.Config.BranchPrefix(config.BranchTypes[p.branchType])+"…") + "\n"
+	case err != nil:
+		view += ui.SuggestionStyle.Render("Name:        ") + ui.WarningStyle.Padding(0).Render(name+"  ⚠ "+err.Error()) + "\n"
+	default:
+		view += ui.SuggestionStyle.Render("Name:        ") + ui.StatusBarBranchStyle.Render(name) + "\n"
+	}
+	view += ui.BlurredStyle.Render(fmt.Sprintf("  %s naming, prefixes from BRANCH_PREFIX_* in /config", m.Config.BranchNamingStyle)) + "\n\n"
+
+	if p.suggesting {
+		view += ui.SuggestionStyle.Render("Asking Gemini for a name…") + "\n"
+	}
+	view += p.status()
+	view += keyHints("tab type", "ctrl+g suggest with Gemini", "enter create and switch", "esc back")
+	return view
+}
+
+func (p *BranchPanel) viewRename() string {
+	var view string
+	view += ui.SuggestionStyle.Render("Rename "+p.renaming) + "\n\n"
+	view += ui.SuggestionStyle.Render("New name: ") + p.input.View() + "\n"
+	if err := git.ValidateBranchName(strings.TrimSpace(p.input.Value())); err != nil {
+		view += ui.WarningStyle.Render("⚠ "+err.Error()) + "\n"
+	}
+	view += "\n"
+	view += p.status()
+	view += keyHints("enter rename", "esc back")
+	return view
+}
+
+func (p *BranchPanel) status() string {
+	if p.err != "" {
+		return ui.WarningStyle.Render("❌ "+p.err) + "\n"
+	}
+	if p.message != "" {
+		return ui.SuggestionStyle.Render("✓ "+p.message) + "\n"
+	}
+	return ""
+}
@@ -0,0 +1,402 @@
+package panels
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/control"
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/sanitize"
+	"gemini-orchestrator/internal/ui"
+	"gemini-orchestrator/internal/utils"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// commitPrompt and commitInstructions are the prompt of build_commit_prompt
+// in utils/generators/commit_message_generator.zsh, around the feedback and
+// the user's context
+const (
+	commitPrompt       = "Based on the git diff and recent commit history above, generate a concise, conventional commit message (e.g., 'feat:', 'fix:', 'docs:', etc.)."
+	commitInstructions = "Focus on what changed and why, considering the recent development context. IMPORTANT: Start with the commit title on the first line immediately - do NOT wrap the commit message in code blocks (``` marks). Use a bullet list under the title with dashes (-) for bullet points:"
+)
+
+// geminiAttribution ends generated commit messages and pull request
+// bodies, as in the scripts
+const geminiAttribution = "🤖 Generated with [Gemini CLI](https://github.com/google-gemini/gemini-cli)"
+
+// maxCommitDiff bounds the staged diff sent to Gemini
+const maxCommitDiff = 100000
+
+// CommitInput is what a commit message is generated from, gathered like
+// auto_commit.zsh does before calling build_commit_prompt
+type CommitInput struct {
+	Root       string
+	Branch     string
+	Files      []string // Staged paths
+	Diff       string   // Staged changes
+	Recent     string   // Recent commits, one per line
+	Repository string   // "owner/name" or the origin URL
+	Context    string   // GEMINI.md context
+	Extra      string   // Context typed after /commit
+	Issue      int      // Issue the branch was started for, 0 when none
+	DryRun     bool     // Show the commit instead of making it
+}
+
+type commitMode int
+
+const (
+	commitGenerating commitMode = iota
+	commitReview
+	commitEdit
+	commitFeedback
+	commitCommitting
+)
+
+// commitDoneMsg reports the commit made from the accepted message
+type commitDoneMsg struct {
+	sha string
+	err error
+}
+
+// CommitPanel generates a commit message for the staged changes and lets
+// the user accept, edit or regenerate it with feedback, the loop of
+// generate_commit_message without leaving the orchestrator
+type CommitPanel struct {
+	input    CommitInput
+	mode     commitMode
+	stream   *llm.Stream
+	reply    string   // Message streaming in
+	message  string   // Message to commit, as generated or edited
+	previous string   // Last generated message, shown to Gemini with feedback
+	feedback []string // Cumulative feedback, one entry per regeneration
+	editor   textarea.Model
+	question textinput.Model
+	status   string
+	err      string
+}
+
+// NewCommitPanel prepares the panel; Generate starts the first message
+func NewCommitPanel(input CommitInput) *CommitPanel {
+	p := &CommitPanel{input: input, editor: newEditor()}
+	p.question = textinput.New()
+	p.question.Prompt = ""
+	p.question.CharLimit = 500
+	p.question.Placeholder = "e.g. mention the config migration, shorter title"
+	p.question.Cursor.SetMode(cursor.CursorStatic)
+	return p
+}
+
+// Generate streams a new message from Gemini, taking the feedback given
+// so far into account
+func (p *CommitPanel) Generate(m *models.Model) tea.Cmd {
+	backend, model, err := m.LLM()
+	if err != nil {
+		p.err = err.Error()
+		p.mode = commitReview
+		return nil
+	}
+	p.mode, p.reply, p.status, p.err = commitGenerating, "", "", ""
+	p.stream = backend.Stream(p.request(model))
+	return p.stream.Next()
+}
+
+// request builds the prompt the way build_commit_prompt does; the
+// repository, history and diff go first so the instructions come last
+func (p *CommitPanel) request(model string) llm.Request {
+	var input strings.Builder
+	if p.input.Repository != "" {
+		fmt.Fprintf(&input, "Repository context:\nRepository: %s\nCurrent branch: %s\n\n", p.input.Repository, p.input.Branch)
+	}
+	if p.input.Context != "" {
+		fmt.Fprintf(&input, "Repository context from GEMINI.md:\n%s\n\n", p.input.Context)
+	}
+	diff := p.input.Diff
+	if len(diff) > maxCommitDiff {
+		diff = utils.TruncateBytes(diff, maxCommitDiff) + "\n[diff truncated]\n"
+	}
+	fmt.Fprintf(&input, "Recent commits for context:\n%s\n\nCurrent staged changes:\n%s", p.input.Recent, diff)
+
+	prompt := commitPrompt
+	if p.previous != "" && len(p.feedback) > 0 {
+		prompt += fmt.Sprintf("\n\nThe previous attempt was:\n---\n%s\n---\n\nPlease incorporate the following cumulative feedback to improve the message:\n- %s",
+			p.previous, strings.Join(p.feedback, "\n- "))
+	}
+	if p.input.Extra != "" {
+		prompt += "\n\nAdditional context from user: " + p.input.Extra
+	}
+	prompt += "\n\n" + commitInstructions
+
+	return llm.Request{Model: model, Prompt: prompt, Input: input.String()}
+}
+
+// fullMessage is what gets committed: the message, the attribution and a
+// reference to the branch's issue
+func (p *CommitPanel) fullMessage() string {
+	return github.AppendIssueReference(p.message+"\n\n"+geminiAttribution, p.input.Issue)
+}
+
+func (p *CommitPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case llm.ChunkMsg:
+		if msg.Stream != p.stream {
+			return nil
+		}
+		p.reply += msg.Text
+		return p.stream.Next()
+	case llm.DoneMsg:
+		if msg.Stream != p.stream {
+			return nil
+		}
+		return p.finishGenerating(msg.Err, m)
+	case commitDoneMsg:
+		if msg.err != nil {
+			p.mode, p.err = commitReview, msg.err.Error()
+			return nil
+		}
+		subject, _, _ := strings.Cut(p.message, "\n")
+		m.AddResult(fmt.Sprintf("Committed %.7s %s", msg.sha, subject))
+		m.Publish(control.EventCommitCreated, map[string]string{"sha": msg.sha, "subject": subject})
+		m.Panel = nil
+		return nil
+	case tea.KeyMsg:
+		switch p.mode {
+		case commitGenerating:
+			if msg.String() == "esc" {
+				p.stream.Cancel()
+			}
+		case commitReview:
+			return p.updateReview(msg, m)
+		case commitEdit:
+			return p.updateEdit(msg, m)
+		case commitFeedback:
+			return p.updateFeedback(msg, m)
+		}
+	}
+	return nil
+}
+
+// finishGenerating shows the new message, or goes back to the previous one
+// when generating was cancelled or failed
+func (p *CommitPanel) finishGenerating(err error, m *models.Model) tea.Cmd {
+	p.stream = nil
+	p.mode = commitReview
+
+	switch {
+	case errors.Is(err, llm.ErrCancelled):
+		if p.message == "" {
+			m.AddResult("Commit cancelled")
+			m.Panel = nil
+			return nil
+		}
+		p.status = "Kept the previous message"
+		return nil
+	case err != nil:
+		p.err = err.Error()
+		return nil
+	}
+
+	message, err := sanitize.Text(p.reply)
+	if err != nil {
+		p.err = err.Error()
+		return nil
+	}
+	p.message, p.previous = message, message
+	return nil
+}
+
+func (p *CommitPanel) updateReview(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "enter", "y":
+		if p.message == "" {
+			return nil
+		}
+		return p.commit(m)
+	case "e":
+		if p.message == "" {
+			return nil
+		}
+		p.mode, p.status, p.err = commitEdit, "", ""
+		p.resize(m)
+		p.editor.SetValue(p.message)
+		return p.editor.Focus()
+	case "r":
+		p.mode, p.status, p.err = commitFeedback, "", ""
+		p.question.SetValue("")
+		return p.question.Focus()
+	case "esc", "q":
+		m.AddResult("Commit cancelled")
+		m.Panel = nil
+	}
+	return nil
+}
+
+func (p *CommitPanel) updateEdit(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+s":
+		message := strings.TrimSpace(p.editor.Value())
+		if message == "" {
+			p.err = "The commit message cannot be empty"
+			return nil
+		}
+		// Feedback refers to the message as edited, like "Append text" in auto-commit
+		p.message, p.previous = message, message
+		p.mode, p.status, p.err = commitReview, "Message edited", ""
+		p.editor.Blur()
+		return nil
+	case "esc":
+		p.mode, p.status, p.err = commitReview, "Discarded the edits", ""
+		p.editor.Blur()
+		return nil
+	}
+
+	p.resize(m)
+	var cmd tea.Cmd
+	p.editor, cmd = p.editor.Update(msg)
+	p.err = ""
+	return cmd
+}
+
+func (p *CommitPanel) updateFeedback(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		if feedback := strings.TrimSpace(p.question.Value()); feedback != "" {
+			p.feedback = append(p.feedback, feedback)
+		}
+		p.question.Blur()
+		return p.Generate(m)
+	case "esc":
+		p.mode = commitReview
+		p.question.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.question, cmd = p.question.Update(msg)
+	return cmd
+}
+
+// commit records the staged changes, or only reports what would be
+// committed in dry-run mode
+func (p *CommitPanel) commit(m *models.Model) tea.Cmd {
+	message := p.fullMessage()
+	if p.input.DryRun {
+		subject, _, _ := strings.Cut(message, "\n")
+		m.AddResult("🔍 Dry run: would commit " + subject)
+		m.Panel = nil
+		return nil
+	}
+
+	p.mode, p.status, p.err = commitCommitting, "", ""
+	root := p.input.Root
+	return func() tea.Msg {
+		sha, err := git.CommitStaged(root, message)
+		return commitDoneMsg{sha: sha, err: err}
+	}
+}
+
+// resize fits the editor to the terminal
+func (p *CommitPanel) resize(m *models.Model) {
+	p.editor.SetWidth(max(20, m.Width-4))
+	p.editor.SetHeight(listHeight(m.Height, 8))
+}
+
+func (p *CommitPanel) View(m models.Model) string {
+	var view string
+
+	title := "Commit to " + p.input.Branch
+	if p.input.Branch == "" {
+		title = "Commit"
+	}
+	details := fmt.Sprintf("  %d staged file(s)", len(p.input.Files))
+	if model, _ := m.Config.NativeModel(m.Overrides); model != "" {
+		details += " • " + model
+	}
+	if p.input.DryRun {
+		details += " • dry run"
+	}
+	view += ui.SuggestionStyle.Render(title+ui.BlurredStyle.Render(details)) + "\n\n"
+
+	width := max(20, m.Width-4)
+	height := listHeight(m.Height, 10)
+	switch p.mode {
+	case commitGenerating:
+		view += p.viewMessage(p.reply, width, height)
+		view += "\n" + ui.SuggestionStyle.Render("✦ Gemini is writing the commit message…") + "\n"
+		view += keyHints("esc cancel")
+		return view
+	case commitEdit:
+		view += p.editor.View() + "\n\n"
+		view += p.statusLine()
+		view += keyHints("ctrl+s save", "esc discard edits")
+		return view
+	}
+
+	if p.message != "" {
+		view += p.viewMessage(p.message, width, height-3)
+		appended := []string{geminiAttribution}
+		if p.fullMessage() != p.message+"\n\n"+geminiAttribution {
+			appended = append(appended, fmt.Sprintf("resolves #%d", p.input.Issue))
+		}
+		view += ui.BlurredStyle.Render("  + "+strings.Join(appended, "\n  + ")) + "\n"
+	}
+	if len(p.feedback) > 0 {
+		view += ui.BlurredStyle.Render(fmt.Sprintf("  Feedback so far: %s", strings.Join(p.feedback, "; "))) + "\n"
+	}
+	view += "\n"
+
+	switch p.mode {
+	case commitFeedback:
+		view += ui.SuggestionStyle.Render("Feedback: ") + p.question.View() + "\n\n"
+		view += keyHints("enter regenerate", "esc back")
+	case commitCommitting:
+		view += ui.SuggestionStyle.Render("Committing…") + "\n"
+	default:
+		view += p.statusLine()
+		if p.message == "" {
+			view += keyHints("r retry", "esc close")
+		} else {
+			view += keyHints("enter commit", "e edit", "r regenerate with feedback", "esc cancel")
+		}
+	}
+	return view
+}
+
+// viewMessage shows a commit message wrapped to width, its subject
+// highlighted and the end cut to height lines
+func (p *CommitPanel) viewMessage(message string, width, height int) string {
+	wrapped := lipgloss.NewStyle().Width(width).Render(message)
+	lines := strings.Split(wrapped, "\n")
+	if len(lines) > height {
+		lines = append(lines[:max(1, height-1)], "…")
+	}
+
+	var view string
+	for i, line := range lines {
+		line = strings.TrimRight(line, " ")
+		if i == 0 {
+			view += "  " + ui.StagedStyle.Bold(true).Render(line) + "\n"
+		} else {
+			view += "  " + ui.MessageStyle.Render(line) + "\n"
+		}
+	}
+	return view
+}
+
+func (p *CommitPanel) statusLine() string {
+	if p.err != "" {
+		return ui.WarningStyle.Render("❌ "+p.err) + "\n"
+	}
+	if p.status != "" {
+		return ui.SuggestionStyle.Render("✓ "+p.status) + "\n"
+	}
+	return ""
+}
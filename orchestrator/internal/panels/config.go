@@ -0,0 +1,249 @@
+package panels
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/ui"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ConfigPanel lists every effective configuration key with its provenance
+// and edits values in a chosen tier
+type ConfigPanel struct {
+	keys     []string
+	selected int
+	editing  bool
+	tier     int // Index into config.Tiers while editing
+	input    textinput.Model
+	err      string
+}
+
+// NewConfigPanel opens the editor on the model's current configuration
+func NewConfigPanel(m *models.Model) *ConfigPanel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.CharLimit = 200
+
+	p := &ConfigPanel{input: input}
+	p.refresh(m.Config)
+	return p
+}
+
+// refresh lists the known keys followed by any unknown ones found in files
+func (p *ConfigPanel) refresh(c *config.Config) {
+	p.keys = append([]string{}, config.Keys...)
+
+	var unknown []string
+	for key := range c.Entries {
+		if !config.IsKnown(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	p.keys = append(p.keys, unknown...)
+
+	p.selected = min(p.selected, len(p.keys)-1)
+}
+
+func (p *ConfigPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	if p.editing {
+		return p.updateEditing(key, m)
+	}
+
+	switch key.String() {
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.keys)-1 {
+			p.selected++
+		}
+	case "enter", "e":
+		p.startEditing(m)
+		return textinput.Blink
+	case "x":
+		p.unset(m)
+	case "esc", "q":
+		m.Panel = nil
+	}
+	return nil
+}
+
+func (p *ConfigPanel) startEditing(m *models.Model) {
+	key := p.keys[p.selected]
+	entry := m.Config.Entries[key]
+
+	// Edit in the tier the value currently comes from, or the repository
+	p.tier = 0
+	if index := slices.Index(config.Tiers, entry.Source); index >= 0 {
+		p.tier = index
+	}
+
+	p.editing = true
+	p.err = ""
+	p.input.SetValue(entry.Value)
+	p.input.CursorEnd()
+	p.input.Focus()
+}
+
+func (p *ConfigPanel) updateEditing(key tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch key.Type {
+	case tea.KeyEsc:
+		p.editing = false
+		p.err = ""
+		p.input.Blur()
+		return nil
+	case tea.KeyTab:
+		p.tier = (p.tier + 1) % len(config.Tiers)
+		return nil
+	case tea.KeyShiftTab:
+		p.tier = (p.tier + len(config.Tiers) - 1) % len(config.Tiers)
+		return nil
+	case tea.KeyEnter:
+		p.save(m)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(key)
+	return cmd
+}
+
+func (p *ConfigPanel) save(m *models.Model) {
+	key := p.keys[p.selected]
+	value := strings.TrimSpace(p.input.Value())
+	tier := config.Tiers[p.tier]
+	path := m.Config.Paths.PathFor(tier)
+
+	if err := config.Set(path, key, value); err != nil {
+		p.err = err.Error()
+		return
+	}
+	if err := reloadConfig(m); err != nil {
+		p.err = err.Error()
+		return
+	}
+
+	m.AddResult(fmt.Sprintf("Set %s=%s in %s (%s)", key, value, tier, path))
+	p.editing = false
+	p.err = ""
+	p.input.Blur()
+	p.refresh(m.Config)
+}
+
+// unset removes the selected key from the file it is currently set in
+func (p *ConfigPanel) unset(m *models.Model) {
+	key := p.keys[p.selected]
+	entry := m.Config.Entries[key]
+	if entry.Path == "" {
+		p.err = fmt.Sprintf("%s is not set in any file", key)
+		return
+	}
+
+	if err := config.Unset(entry.Path, key); err != nil {
+		p.err = err.Error()
+		return
+	}
+	if err := reloadConfig(m); err != nil {
+		p.err = err.Error()
+		return
+	}
+
+	m.AddResult(fmt.Sprintf("Removed %s from %s (%s)", key, entry.Source, entry.Path))
+	p.err = ""
+	p.refresh(m.Config)
+}
+
+func (p *ConfigPanel) View(m models.Model) string {
+	var view string
+	view += ui.SuggestionStyle.Render("Effective configuration") + "\n"
+	if m.Overrides.Active() {
+		view += ui.WarningStyle.Render("Session overrides: "+m.Overrides.String()) + "\n"
+	}
+	view += "\n"
+
+	problems := m.Config.Problems()
+	keyWidth := 0
+	for _, key := range p.keys {
+		keyWidth = max(keyWidth, len(key))
+	}
+
+	// Title, blank line, editor and hints take roughly ten lines
+	start, end := visibleRange(p.selected, len(p.keys), listHeight(m.Height, 12))
+	for i := start; i < end; i++ {
+		key := p.keys[i]
+		entry := m.Config.Entries[key]
+
+		origin := string(entry.Source)
+		if entry.Path != "" {
+			origin = fmt.Sprintf("%s  %s:%d", entry.Source, entry.Path, entry.Line)
+		}
+		value := entry.Value
+		if value == "" {
+			value = "(unset)"
+		}
+		if key == config.KeyGeminiModel && m.Overrides.Model != "" {
+			value = m.Overrides.Model
+			origin = fmt.Sprintf("%s  /model overrides %s from %s", config.SourceSession, entry.Value, origin)
+		}
+
+		line := fmt.Sprintf("%-*s  %-20s  ", keyWidth, key, truncate(value, 20))
+		style := ui.SuggestionStyle
+		if i == p.selected {
+			style = ui.SelectedSuggestionStyle
+		}
+		row := style.Render(line) + ui.BlurredStyle.Render(truncate(origin, max(10, m.Width-keyWidth-30)))
+		if problem, ok := problems[key]; ok {
+			row += "\n" + ui.WarningStyle.Render("    ⚠ "+problem)
+		}
+		view += row + "\n"
+	}
+	view += "\n"
+
+	if p.editing {
+		var tiers []string
+		for i, tier := range config.Tiers {
+			label := string(tier)
+			if i == p.tier {
+				label = "[" + label + "]"
+			}
+			tiers = append(tiers, label)
+		}
+		path := m.Config.Paths.PathFor(config.Tiers[p.tier])
+		view += ui.SuggestionStyle.Render(fmt.Sprintf("Set %s in %s  %s", p.keys[p.selected], strings.Join(tiers, " "), path)) + "\n"
+		view += ui.InputBoxStyle.Width(m.Width-2).Render(p.input.View()) + "\n"
+	}
+
+	if p.err != "" {
+		view += ui.WarningStyle.Render("  ❌ "+p.err) + "\n"
+	}
+
+	if p.editing {
+		view += keyHints("tab change tier", "enter save", "esc cancel")
+	} else {
+		view += keyHints("↑/↓ select", "enter edit", "x remove from its file", "esc close")
+	}
+	return view
+}
+
+// reloadConfig re-reads every tier after a file was changed
+func reloadConfig(m *models.Model) error {
+	c, err := config.Load(m.Config.Paths)
+	if err != nil {
+		return err
+	}
+	m.Config = c
+	return nil
+}
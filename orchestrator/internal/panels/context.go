@@ -0,0 +1,119 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/repocontext"
+	"gemini-orchestrator/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ContextPanel shows which GEMINI.md sections fit the token budget and the
+// exact context text sent with each request
+type ContextPanel struct {
+	context *repocontext.Context
+	lines   []contextLine
+	offset  int
+}
+
+type contextLine struct {
+	text  string
+	style lipgloss.Style
+	note  string // Dimmed after the text, e.g. a token count
+}
+
+// NewContextPanel lays out a loaded context
+func NewContextPanel(context *repocontext.Context) *ContextPanel {
+	p := &ContextPanel{context: context}
+
+	for _, f := range context.Files {
+		kept, total := f.Tokens()
+		p.lines = append(p.lines, contextLine{f.Name, ui.DiffFileStyle, fmt.Sprintf("%d/%d tokens", kept, total)})
+		for _, s := range f.Sections {
+			title := s.Title
+			if title == "" {
+				title = "(introduction)"
+			}
+			line := contextLine{"  ✓ " + title, ui.StagedStyle, fmt.Sprintf("%d tokens", s.Tokens)}
+			if !s.Kept {
+				line.text, line.style = "  ✗ "+title, ui.UnstagedStyle
+			}
+			p.lines = append(p.lines, line)
+		}
+		p.lines = append(p.lines, contextLine{style: ui.MessageStyle})
+	}
+
+	if text := context.Text(); text != "" {
+		p.lines = append(p.lines, contextLine{"Sent with each request:", ui.BlurredStyle, ""}, contextLine{style: ui.MessageStyle})
+		for _, line := range strings.Split(text, "\n") {
+			p.lines = append(p.lines, contextLine{line, ui.MessageStyle, ""})
+		}
+	}
+	return p
+}
+
+func (p *ContextPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	page := p.height(m)
+	switch key.String() {
+	case "up", "k":
+		p.scroll(-1, m)
+	case "down", "j":
+		p.scroll(1, m)
+	case "pgup", "b":
+		p.scroll(-page, m)
+	case "pgdown", " ":
+		p.scroll(page, m)
+	case "home", "g":
+		p.offset = 0
+	case "end", "G":
+		p.scroll(len(p.lines), m)
+	case "esc", "q":
+		m.Panel = nil
+	}
+	return nil
+}
+
+func (p *ContextPanel) height(m *models.Model) int {
+	return listHeight(m.Height, 10)
+}
+
+func (p *ContextPanel) scroll(delta int, m *models.Model) {
+	last := max(0, len(p.lines)-p.height(m))
+	p.offset = max(0, min(p.offset+delta, last))
+}
+
+func (p *ContextPanel) View(m models.Model) string {
+	var view string
+
+	title := "Context" + ui.BlurredStyle.Render(fmt.Sprintf("  %d/%d tokens • %d file(s)", p.context.Tokens, p.context.Budget, len(p.context.Files)))
+	if omitted := p.context.Omitted(); omitted > 0 {
+		title += ui.BlurredStyle.Render(fmt.Sprintf(" • %d section(s) over budget", omitted))
+	}
+	view += ui.SuggestionStyle.Render(title) + "\n\n"
+
+	if len(p.lines) == 0 {
+		view += ui.SuggestionStyle.Render("No "+repocontext.FileName+" found in the repository root or the working directory") + "\n"
+	} else {
+		width := max(20, m.Width-2)
+		end := min(p.offset+p.height(&m), len(p.lines))
+		for _, line := range p.lines[p.offset:end] {
+			text := line.style.Render(truncate(line.text, width))
+			if line.note != "" {
+				text += ui.BlurredStyle.Render("  " + line.note)
+			}
+			view += "  " + text + "\n"
+		}
+	}
+	view += "\n"
+
+	view += keyHints("↑/↓ scroll", "space/b page", "esc close")
+	return view
+}
@@ -0,0 +1,547 @@
+package panels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/highlight"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sideBySideWidth is the terminal width from which diffs are shown side by
+// side unless the user picked a layout
+const sideBySideWidth = 160
+
+// diffLoadedMsg carries a reloaded diff
+type diffLoadedMsg struct {
+	files []git.FileDiff
+	err   error
+}
+
+type diffRowKind int
+
+const (
+	rowMessage diffRowKind = iota
+	rowFile
+	rowNote
+	rowHunk
+	rowCode
+)
+
+// diffRow is one screen line of the viewer
+type diffRow struct {
+	kind  diffRowKind
+	file  int       // Index into the panel's files
+	text  string    // Message, file, note or hunk header text
+	left  *diffCell // The line in unified layout, the old side side by side
+	right *diffCell // The new side side by side
+}
+
+// diffCell is a code line ready to render
+type diffCell struct {
+	line     git.Line
+	text     string              // Tabs expanded
+	emphasis []highlight.Range   // Words changed within the line
+	lang     *highlight.Language // Nil renders plain text
+}
+
+// DiffPanel is a full-height, scrollable diff viewer with syntax and
+// word-level highlights, a file jump list, and a side-by-side layout on
+// wide terminals
+type DiffPanel struct {
+	root       string
+	options    git.DiffOptions
+	title      string
+	files      []git.FileDiff
+	rows       map[bool][]diffRow // Built lazily, keyed by side by side
+	offset     int                // First visible row
+	sideBySide *bool              // Chosen layout, nil picks by width
+	showFiles  bool               // File jump list is open
+	fileCursor int
+	header     []string // Lines shown above the diff, such as a commit message
+	actions    []string // Key hints for keys handled by the panel embedding this one
+	embedded   bool     // Esc returns to the embedding panel rather than closing
+	loading    bool     // The first diff is still running
+	message    string
+	err        string
+}
+
+// NewDiffPanel shows the result of git.Diff with options in root once Load
+// has run it; an empty or failed first diff closes the panel. title
+// describes what is being compared.
+func NewDiffPanel(root string, options git.DiffOptions, title string) *DiffPanel {
+	p := newDiffPanel(root, options, title, nil)
+	p.loading = true
+	return p
+}
+
+// newDiffPanel shows files already diffed with options in root
+func newDiffPanel(root string, options git.DiffOptions, title string, files []git.FileDiff) *DiffPanel {
+	return &DiffPanel{root: root, options: options, title: title, files: files, rows: map[bool][]diffRow{}}
+}
+
+// Load runs the diff again
+func (p *DiffPanel) Load() tea.Cmd {
+	root, options := p.root, p.options
+	return func() tea.Msg {
+		files, err := git.Diff(root, options)
+		return diffLoadedMsg{files: files, err: err}
+	}
+}
+
+func (p *DiffPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case diffLoadedMsg:
+		if p.loading {
+			p.loading = false
+			switch {
+			case msg.err != nil:
+				m.AddResult(fmt.Sprintf("❌ %v", msg.err))
+				m.Panel = nil
+				return nil
+			case len(msg.files) == 0:
+				m.AddResult("No changes")
+				m.Panel = nil
+				return nil
+			}
+		}
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		// Stay on the same file when it is still part of the diff
+		current := p.currentFilePath(m.Width)
+		p.files, p.rows, p.err = msg.files, map[bool][]diffRow{}, ""
+		p.offset = 0
+		for i, f := range p.files {
+			if f.Path() == current {
+				p.jumpToFile(i, m)
+			}
+		}
+		p.fileCursor = min(p.fileCursor, max(0, len(p.files)-1))
+	case tea.KeyMsg:
+		if p.showFiles {
+			p.updateFileList(msg, m)
+			return nil
+		}
+
+		page := p.height(m)
+		switch msg.String() {
+		case "up", "k":
+			p.scroll(-1, m)
+		case "down", "j":
+			p.scroll(1, m)
+		case "pgup", "b":
+			p.scroll(-page, m)
+		case "pgdown", " ":
+			p.scroll(page, m)
+		case "ctrl+u":
+			p.scroll(-page/2, m)
+		case "ctrl+d":
+			p.scroll(page/2, m)
+		case "home", "g":
+			p.offset = 0
+		case "end", "G":
+			p.scroll(len(p.layoutRows(m.Width)), m)
+		case "]":
+			p.jumpToFile(p.currentFile(m.Width)+1, m)
+		case "[":
+			p.jumpToRow(m, -1, rowFile)
+		case "n":
+			p.jumpToRow(m, 1, rowHunk)
+		case "p":
+			p.jumpToRow(m, -1, rowHunk)
+		case "f", "tab":
+			if len(p.files) > 0 {
+				p.showFiles = true
+				p.fileCursor = p.currentFile(m.Width)
+			}
+		case "v":
+			// Keep the current file in view when the layout changes
+			file := p.currentFile(m.Width)
+			split := !p.isSideBySide(m.Width)
+			p.sideBySide = &split
+			p.offset = 0
+			p.jumpToFile(file, m)
+		case "r":
+			return p.Load()
+		case "esc", "q":
+			m.Panel = nil
+		}
+	}
+	return nil
+}
+
+func (p *DiffPanel) updateFileList(msg tea.KeyMsg, m *models.Model) {
+	switch msg.String() {
+	case "up", "k":
+		p.fileCursor = max(0, p.fileCursor-1)
+	case "down", "j":
+		p.fileCursor = min(len(p.files)-1, p.fileCursor+1)
+	case "enter":
+		p.showFiles = false
+		p.jumpToFile(p.fileCursor, m)
+	case "f", "tab", "esc", "q":
+		p.showFiles = false
+	}
+}
+
+func (p *DiffPanel) isSideBySide(width int) bool {
+	if p.sideBySide != nil {
+		return *p.sideBySide
+	}
+	return width >= sideBySideWidth
+}
+
+// setHeader replaces the lines shown above the diff
+func (p *DiffPanel) setHeader(lines []string) {
+	p.header = lines
+	p.rows = map[bool][]diffRow{}
+}
+
+func (p *DiffPanel) layoutRows(width int) []diffRow {
+	split := p.isSideBySide(width)
+	rows, ok := p.rows[split]
+	if !ok {
+		rows = nil
+		for _, line := range p.header {
+			rows = append(rows, diffRow{kind: rowMessage, text: line})
+		}
+		rows = append(rows, buildDiffRows(p.files, split)...)
+		p.rows[split] = rows
+	}
+	return rows
+}
+
+// height is the number of rows that fit below the title
+func (p *DiffPanel) height(m *models.Model) int {
+	return listHeight(m.Height, 11)
+}
+
+func (p *DiffPanel) scroll(delta int, m *models.Model) {
+	last := max(0, len(p.layoutRows(m.Width))-p.height(m))
+	p.offset = max(0, min(p.offset+delta, last))
+}
+
+// currentFile is the file whose rows are at the top of the screen
+func (p *DiffPanel) currentFile(width int) int {
+	rows := p.layoutRows(width)
+	if p.offset < len(rows) {
+		return rows[p.offset].file
+	}
+	return 0
+}
+
+func (p *DiffPanel) currentFilePath(width int) string {
+	if file := p.currentFile(width); file < len(p.files) {
+		return p.files[file].Path()
+	}
+	return ""
+}
+
+func (p *DiffPanel) jumpToFile(file int, m *models.Model) {
+	for i, row := range p.layoutRows(m.Width) {
+		if row.kind == rowFile && row.file == file {
+			p.offset = 0
+			p.scroll(i, m)
+			return
+		}
+	}
+}
+
+// jumpToRow scrolls to the next row of kind in direction; going back from
+// inside a file or hunk first returns to its own header
+func (p *DiffPanel) jumpToRow(m *models.Model, direction int, kind diffRowKind) {
+	rows := p.layoutRows(m.Width)
+	for i := p.offset + direction; i >= 0 && i < len(rows); i += direction {
+		if rows[i].kind == kind {
+			p.offset = 0
+			p.scroll(i, m)
+			return
+		}
+	}
+}
+
+// buildDiffRows flattens files into screen rows. Runs of removed lines are
+// paired with the added lines that follow them for word-level highlights,
+// and side by side share a row.
+func buildDiffRows(files []git.FileDiff, sideBySide bool) []diffRow {
+	var rows []diffRow
+	for index, file := range files {
+		added, removed := file.Stats()
+		header := fmt.Sprintf("%s  +%d -%d", file.Path(), added, removed)
+		switch {
+		case file.OldPath == "":
+			header += "  (new file)"
+		case file.NewPath == "":
+			header += "  (deleted)"
+		case file.OldPath != file.NewPath:
+			header += "  (renamed from " + file.OldPath + ")"
+		}
+		rows = append(rows, diffRow{kind: rowFile, file: index, text: header})
+
+		if file.Binary {
+			rows = append(rows, diffRow{kind: rowNote, file: index, text: "Binary file changed"})
+		} else if len(file.Hunks) == 0 {
+			rows = append(rows, diffRow{kind: rowNote, file: index, text: "Mode change only"})
+		}
+
+		lang := highlight.Detect(file.Path())
+		for _, hunk := range file.Hunks {
+			rows = append(rows, diffRow{kind: rowHunk, file: index, text: hunk.Header()})
+
+			lines := hunk.Lines
+			for i := 0; i < len(lines); {
+				switch lines[i].Kind {
+				case git.LineNoNewline:
+					if !sideBySide {
+						rows = append(rows, diffRow{kind: rowNote, file: index, text: lines[i].Text})
+					}
+					i++
+					continue
+				case git.LineContext:
+					cell := newDiffCell(lines[i], lang)
+					row := diffRow{kind: rowCode, file: index, left: cell}
+					if sideBySide {
+						row.right = cell
+					}
+					rows = append(rows, row)
+					i++
+					continue
+				}
+
+				var removedCells, addedCells []*diffCell
+				for ; i < len(lines) && lines[i].Kind == git.LineRemoved; i++ {
+					removedCells = append(removedCells, newDiffCell(lines[i], lang))
+				}
+				for ; i < len(lines) && lines[i].Kind == git.LineAdded; i++ {
+					addedCells = append(addedCells, newDiffCell(lines[i], lang))
+				}
+				for k := 0; k < min(len(removedCells), len(addedCells)); k++ {
+					before, after := removedCells[k], addedCells[k]
+					if oldRanges, newRanges, ok := highlight.WordDiff(before.text, after.text); ok {
+						before.emphasis, after.emphasis = oldRanges, newRanges
+					}
+				}
+
+				if sideBySide {
+					for k := 0; k < max(len(removedCells), len(addedCells)); k++ {
+						row := diffRow{kind: rowCode, file: index}
+						if k < len(removedCells) {
+							row.left = removedCells[k]
+						}
+						if k < len(addedCells) {
+							row.right = addedCells[k]
+						}
+						rows = append(rows, row)
+					}
+					continue
+				}
+				for _, cell := range append(removedCells, addedCells...) {
+					rows = append(rows, diffRow{kind: rowCode, file: index, left: cell})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func newDiffCell(line git.Line, lang *highlight.Language) *diffCell {
+	return &diffCell{line: line, text: strings.ReplaceAll(line.Text, "\t", "    "), lang: lang}
+}
+
+func (p *DiffPanel) View(m models.Model) string {
+	var view string
+
+	width := m.Width
+	if width <= 0 {
+		width = 80
+	}
+	rows := p.layoutRows(width)
+	split := p.isSideBySide(width)
+
+	title := "Diff  " + p.title
+	if len(p.files) > 0 {
+		added, removed := 0, 0
+		for _, f := range p.files {
+			a, r := f.Stats()
+			added, removed = added+a, removed+r
+		}
+		layout := "unified"
+		if split {
+			layout = "side by side"
+		}
+		title += ui.BlurredStyle.Render(fmt.Sprintf("  file %d/%d  +%d -%d  %s",
+			p.currentFile(width)+1, len(p.files), added, removed, layout))
+	}
+	view += ui.SuggestionStyle.Render(title) + "\n\n"
+
+	height := listHeight(m.Height, 11)
+	switch {
+	case p.showFiles:
+		view += p.renderFileList(height, width)
+	case p.loading:
+		view += ui.SuggestionStyle.Render("Loading the diff…") + "\n"
+	case len(rows) == 0 && p.err == "":
+		view += ui.SuggestionStyle.Render("No changes") + "\n"
+	default:
+		end := min(p.offset+height, len(rows))
+		for _, row := range rows[p.offset:end] {
+			view += "  " + p.renderRow(row, width-2, split) + "\n"
+		}
+	}
+	view += "\n"
+
+	if p.err != "" {
+		view += ui.WarningStyle.Render("❌ "+p.err) + "\n"
+	} else if p.message != "" {
+		view += ui.SuggestionStyle.Render(p.message) + "\n"
+	}
+
+	if p.showFiles {
+		view += keyHints("↑/↓ select", "enter jump", "esc back")
+	} else {
+		hints := []string{"↑/↓ scroll", "space/b page", "n/p hunk", "]/[ file", "f files", "v layout", "r reload"}
+		hints = append(hints, p.actions...)
+		if p.embedded {
+			hints = append(hints, "esc back")
+		} else {
+			hints = append(hints, "esc close")
+		}
+		view += keyHints(hints...)
+	}
+	return view
+}
+
+func (p *DiffPanel) renderFileList(height, width int) string {
+	var view string
+	start, end := visibleRange(p.fileCursor, len(p.files), height)
+	for i := start; i < end; i++ {
+		f := p.files[i]
+		added, removed := f.Stats()
+		stats := ui.DiffAddedStyle.Render(fmt.Sprintf("+%d", added)) + " " + ui.DiffRemovedStyle.Render(fmt.Sprintf("-%d", removed))
+
+		style := ui.SuggestionStyle
+		if i == p.fileCursor {
+			style = ui.SelectedSuggestionStyle
+		}
+		view += style.Render(truncate(f.Path(), max(10, width-20))+"  ") + stats + "\n"
+	}
+	return view
+}
+
+func (p *DiffPanel) renderRow(row diffRow, width int, split bool) string {
+	switch row.kind {
+	case rowMessage:
+		return ui.MessageStyle.Render(truncate(row.text, width))
+	case rowFile:
+		return ui.DiffFileStyle.Render("▌ " + truncate(row.text, max(10, width-2)))
+	case rowNote:
+		return ui.BlurredStyle.Render(truncate(row.text, width))
+	case rowHunk:
+		return ui.DiffHunkStyle.Render(truncate(row.text, width))
+	}
+
+	if !split {
+		cell := row.left
+		gutter := fmt.Sprintf("%5s %5s ", lineNumber(cell.line.OldNumber), lineNumber(cell.line.NewNumber))
+		return ui.BlurredStyle.Render(gutter) + renderCell(cell, width-len(gutter))
+	}
+
+	leftWidth := (width - 3) / 2
+	rightWidth := width - 3 - leftWidth
+	return renderSide(row.left, leftWidth, true) + ui.BlurredStyle.Render(" │ ") + renderSide(row.right, rightWidth, false)
+}
+
+// renderSide renders one half of a side-by-side row; a missing line is blank
+func renderSide(cell *diffCell, width int, old bool) string {
+	if cell == nil {
+		return strings.Repeat(" ", max(0, width))
+	}
+	number := cell.line.NewNumber
+	if old {
+		number = cell.line.OldNumber
+	}
+	gutter := fmt.Sprintf("%5s ", lineNumber(number))
+	return ui.BlurredStyle.Render(gutter) + renderCell(cell, width-len(gutter))
+}
+
+func lineNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// renderCell draws the sign and code of a line in exactly width columns.
+// Syntax colours the text, the line kind its background, and changed words
+// a stronger background.
+func renderCell(cell *diffCell, width int) string {
+	lineStyle, wordStyle := lipgloss.NewStyle(), lipgloss.NewStyle()
+	sign, signStyle := " ", lipgloss.NewStyle()
+	switch cell.line.Kind {
+	case git.LineAdded:
+		lineStyle, wordStyle = ui.DiffAddedLineStyle, ui.DiffAddedWordStyle
+		sign, signStyle = "+", ui.DiffAddedStyle
+	case git.LineRemoved:
+		lineStyle, wordStyle = ui.DiffRemovedLineStyle, ui.DiffRemovedWordStyle
+		sign, signStyle = "-", ui.DiffRemovedStyle
+	}
+	if width < 2 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(signStyle.Inherit(lineStyle).Render(sign))
+	used, available := 0, width-1
+
+	for _, token := range highlight.Tokenize(cell.lang, cell.text) {
+		for start := token.Start; start < token.End && used < available; {
+			end, emphasized := token.End, false
+			for _, r := range cell.emphasis {
+				switch {
+				case r.Start <= start && start < r.End:
+					emphasized, end = true, min(end, r.End)
+				case start < r.Start && r.Start < end:
+					end = r.Start
+				}
+			}
+
+			piece := []rune(cell.text[start:end])
+			if used+len(piece) > available {
+				piece = append(piece[:available-used-1], '…')
+			}
+			background := lineStyle
+			if emphasized {
+				background = wordStyle
+			}
+			b.WriteString(syntaxStyle(token.Kind).Inherit(background).Render(string(piece)))
+			used += len(piece)
+			start = end
+		}
+	}
+	if used < available {
+		b.WriteString(lineStyle.Render(strings.Repeat(" ", available-used)))
+	}
+	return b.String()
+}
+
+func syntaxStyle(kind highlight.Kind) lipgloss.Style {
+	switch kind {
+	case highlight.Keyword:
+		return ui.SyntaxKeywordStyle
+	case highlight.String:
+		return ui.SyntaxStringStyle
+	case highlight.Comment:
+		return ui.SyntaxCommentStyle
+	case highlight.Number:
+		return ui.SyntaxNumberStyle
+	}
+	return lipgloss.NewStyle()
+}
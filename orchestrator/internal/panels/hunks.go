@@ -0,0 +1,349 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hunkDiffMsg carries the reloaded diff of the file being staged
+type hunkDiffMsg struct {
+	path  string
+	diffs []git.FileDiff
+	err   error
+}
+
+// hunkAppliedMsg reports a finished git apply
+type hunkAppliedMsg struct {
+	message string
+	err     error
+}
+
+// HunkPanel stages or unstages individual hunks and lines, like git add -p.
+// It walks the given files in order; each change is applied to the index
+// with git apply --cached (-R to unstage).
+type HunkPanel struct {
+	root     string
+	staged   bool     // Unstage from the index instead of staging from the work tree
+	paths    []string // Files to walk
+	file     int      // Index into paths
+	diff     *git.FileDiff
+	hunks    []git.Hunk // The file's hunks, including any split by the user
+	splits   [][2]int   // Unchanging-side line ranges the user split, split again after every reload
+	hunk     int
+	cursor   int          // Line within the hunk, always on a change
+	selected map[int]bool // Chosen lines; none applies the whole hunk
+	parent   models.Panel // Panel to return to, nil for the conversation
+	message  string
+	err      string
+}
+
+// NewHunkPanel walks paths in root; call Load to read the first file
+func NewHunkPanel(root string, paths []string, staged bool, parent models.Panel) *HunkPanel {
+	return &HunkPanel{root: root, paths: paths, staged: staged, parent: parent, selected: map[int]bool{}}
+}
+
+// Load reads the diff of the current file
+func (p *HunkPanel) Load() tea.Cmd {
+	if p.file >= len(p.paths) {
+		return nil
+	}
+	root, path, staged := p.root, p.paths[p.file], p.staged
+	return func() tea.Msg {
+		diffs, err := git.Diff(root, git.DiffOptions{Staged: staged, Paths: []string{path}})
+		return hunkDiffMsg{path: path, diffs: diffs, err: err}
+	}
+}
+
+func (p *HunkPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case hunkDiffMsg:
+		return p.loaded(msg)
+	case hunkAppliedMsg:
+		p.message, p.err = msg.message, ""
+		if msg.err != nil {
+			p.message, p.err = "", msg.err.Error()
+		}
+		p.selected = map[int]bool{}
+		return tea.Batch(p.Load(), git.RefreshStatus())
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			p.moveCursor(-1)
+		case "down", "j":
+			p.moveCursor(1)
+		case " ":
+			if p.currentHunk() != nil {
+				p.selected[p.cursor] = !p.selected[p.cursor]
+			}
+		case "y", "enter":
+			return p.apply()
+		case "n", "right":
+			p.showHunk(p.hunk + 1)
+		case "p", "left":
+			p.showHunk(p.hunk - 1)
+		case "s":
+			p.split()
+		case "]":
+			return p.showFile(p.file + 1)
+		case "[":
+			return p.showFile(p.file - 1)
+		case "esc", "q":
+			m.Panel = p.parent
+			if p.parent != nil {
+				return git.RefreshStatus()
+			}
+		}
+	}
+	return nil
+}
+
+func (p *HunkPanel) loaded(msg hunkDiffMsg) tea.Cmd {
+	if p.file >= len(p.paths) || msg.path != p.paths[p.file] {
+		return nil
+	}
+	if msg.err != nil {
+		p.err = msg.err.Error()
+		return nil
+	}
+
+	p.diff = nil
+	if len(msg.diffs) > 0 && (len(msg.diffs[0].Hunks) > 0 || msg.diffs[0].Binary) {
+		p.diff = &msg.diffs[0]
+	}
+	if p.diff == nil {
+		// Everything in this file is done, continue with the next one
+		if p.file < len(p.paths)-1 {
+			return p.showFile(p.file + 1)
+		}
+		p.hunks = nil
+		return nil
+	}
+
+	// Stay on the same hunk position after applying the previous one,
+	// with the rest of a split hunk still split
+	p.hunks = p.resplit(p.diff.Hunks)
+	p.showHunk(min(p.hunk, len(p.hunks)-1))
+	return nil
+}
+
+func (p *HunkPanel) showFile(index int) tea.Cmd {
+	if index < 0 || index >= len(p.paths) {
+		return nil
+	}
+	p.file = index
+	p.hunk = 0
+	p.hunks = nil
+	p.splits = nil
+	p.diff = nil
+	return p.Load()
+}
+
+func (p *HunkPanel) showHunk(index int) {
+	if index < 0 || index >= len(p.hunks) {
+		return
+	}
+	p.hunk = index
+	p.selected = map[int]bool{}
+	p.cursor = -1
+	p.moveCursor(1)
+}
+
+func (p *HunkPanel) currentHunk() *git.Hunk {
+	if p.hunk >= len(p.hunks) {
+		return nil
+	}
+	return &p.hunks[p.hunk]
+}
+
+// moveCursor steps to the next change line in direction
+func (p *HunkPanel) moveCursor(direction int) {
+	hunk := p.currentHunk()
+	if hunk == nil {
+		return
+	}
+	for i := p.cursor + direction; i >= 0 && i < len(hunk.Lines); i += direction {
+		if hunk.Lines[i].IsChange() {
+			p.cursor = i
+			return
+		}
+	}
+}
+
+func (p *HunkPanel) split() {
+	hunk := p.currentHunk()
+	if hunk == nil {
+		return
+	}
+	pieces := hunk.Split()
+	if len(pieces) == 1 {
+		p.message = "This hunk cannot be split further"
+		return
+	}
+
+	start, end := p.stableRange(*hunk)
+	p.splits = append(p.splits, [2]int{start, end})
+
+	hunks := append([]git.Hunk{}, p.hunks[:p.hunk]...)
+	hunks = append(hunks, pieces...)
+	p.hunks = append(hunks, p.hunks[p.hunk+1:]...)
+	p.message = fmt.Sprintf("Split into %d hunks", len(pieces))
+	p.showHunk(p.hunk)
+}
+
+// stableRange returns the lines a hunk covers on the side applying it
+// leaves alone: the work tree when staging, HEAD when unstaging. Those
+// numbers still identify the hunk after the index changes.
+func (p *HunkPanel) stableRange(hunk git.Hunk) (start, end int) {
+	if p.staged {
+		return hunk.OldStart, hunk.OldStart + max(hunk.OldCount, 1)
+	}
+	return hunk.NewStart, hunk.NewStart + max(hunk.NewCount, 1)
+}
+
+// resplit splits the reloaded hunks that overlap a hunk the user split.
+// Split always cuts at every group of changes, so this restores the same
+// boundaries for whatever is left of it.
+func (p *HunkPanel) resplit(hunks []git.Hunk) []git.Hunk {
+	if len(p.splits) == 0 {
+		return hunks
+	}
+	var result []git.Hunk
+	for _, hunk := range hunks {
+		start, end := p.stableRange(hunk)
+		split := false
+		for _, r := range p.splits {
+			if start < r[1] && r[0] < end {
+				split = true
+				break
+			}
+		}
+		if split {
+			result = append(result, hunk.Split()...)
+		} else {
+			result = append(result, hunk)
+		}
+	}
+	return result
+}
+
+// apply stages (or unstages) the selected lines, or the whole hunk
+func (p *HunkPanel) apply() tea.Cmd {
+	hunk := p.currentHunk()
+	if hunk == nil {
+		return nil
+	}
+
+	var selected func(int) bool
+	what := "hunk"
+	if count := p.selectionCount(); count > 0 {
+		chosen := p.selected
+		selected = func(i int) bool { return chosen[i] }
+		what = fmt.Sprintf("%d line(s)", count)
+	}
+
+	build, verb := p.diff.Patch, "Staged"
+	if p.staged {
+		build, verb = p.diff.ReversePatch, "Unstaged"
+	}
+	patch, err := build(*hunk, selected)
+	if err != nil {
+		p.err = err.Error()
+		return nil
+	}
+
+	root, reverse, path := p.root, p.staged, p.diff.Path()
+	return func() tea.Msg {
+		return hunkAppliedMsg{
+			message: fmt.Sprintf("%s %s of %s", verb, what, path),
+			err:     git.ApplyCached(root, patch, reverse),
+		}
+	}
+}
+
+func (p *HunkPanel) selectionCount() int {
+	count := 0
+	for _, chosen := range p.selected {
+		if chosen {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *HunkPanel) View(m models.Model) string {
+	var view string
+
+	verb := "stage"
+	if p.staged {
+		verb = "unstage"
+	}
+	title := fmt.Sprintf("%s hunks", strings.ToUpper(verb[:1])+verb[1:])
+	if p.file < len(p.paths) {
+		title += fmt.Sprintf("  %s (file %d/%d)", p.paths[p.file], p.file+1, len(p.paths))
+	}
+	view += ui.SuggestionStyle.Render(title) + "\n\n"
+
+	hunk := p.currentHunk()
+	switch {
+	case p.diff != nil && p.diff.Binary:
+		view += ui.WarningStyle.Render("Binary file, stage it whole from /status") + "\n"
+	case hunk == nil && p.diff == nil && p.err == "":
+		view += ui.SuggestionStyle.Render("No more changes to "+verb) + "\n"
+	case hunk != nil:
+		view += ui.DiffHunkStyle.Render(fmt.Sprintf("  %s", hunk.Header())) +
+			ui.BlurredStyle.Render(fmt.Sprintf("  hunk %d/%d", p.hunk+1, len(p.hunks))) + "\n"
+
+		start, end := visibleRange(p.cursor, len(hunk.Lines), listHeight(m.Height, 12))
+		for i := start; i < end; i++ {
+			view += p.renderLine(hunk.Lines[i], i, m.Width) + "\n"
+		}
+	}
+	view += "\n"
+
+	if p.err != "" {
+		view += ui.WarningStyle.Render("❌ "+p.err) + "\n"
+	} else if p.message != "" {
+		view += ui.SuggestionStyle.Render("✓ "+p.message) + "\n"
+	}
+
+	apply := "y " + verb
+	if p.selectionCount() > 0 {
+		apply += " selected lines"
+	} else {
+		apply += " hunk"
+	}
+	view += keyHints("↑/↓ line", "space select line", apply, "s split", "n/p hunk", "]/[ file", "esc back")
+	return view
+}
+
+func (p *HunkPanel) renderLine(line git.Line, index, width int) string {
+	cursor := "  "
+	if index == p.cursor {
+		cursor = "> "
+	}
+	mark := "   "
+	if line.IsChange() && p.selected[index] {
+		mark = "[x]"
+	} else if line.IsChange() && p.selectionCount() > 0 {
+		mark = "[ ]"
+	}
+
+	text := truncate(strings.ReplaceAll(line.Text, "\t", "    "), max(10, width-12))
+	switch line.Kind {
+	case git.LineAdded:
+		text = ui.DiffAddedStyle.Render("+" + text)
+	case git.LineRemoved:
+		text = ui.DiffRemovedStyle.Render("-" + text)
+	case git.LineNoNewline:
+		text = ui.BlurredStyle.Render(text)
+	default:
+		text = " " + text
+	}
+	return "  " + ui.SelectedSuggestionStyle.Padding(0).Render(cursor) + mark + " " + text
+}
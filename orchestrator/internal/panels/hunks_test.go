@@ -0,0 +1,73 @@
+package panels
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gemini-orchestrator/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestHunkPanelKeepsSplits stages (and unstages) one piece of a split hunk
+// and checks the rest of it is still split after the reload
+func TestHunkPanelKeepsSplits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	for _, staged := range []bool{false, true} {
+		root := t.TempDir()
+		gitIn := func(args ...string) {
+			t.Helper()
+			cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+			}
+		}
+		write := func(lines ...string) {
+			t.Helper()
+			if err := os.WriteFile(filepath.Join(root, "list.txt"), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Three changes two context lines apart make one hunk of three groups
+		gitIn("init", "-q")
+		write("a", "b", "c", "d", "e", "f", "g", "h", "i")
+		gitIn("add", "list.txt")
+		gitIn("commit", "-q", "-m", "initial")
+		write("A", "b", "c", "D", "e", "f", "G", "h", "i")
+		if staged {
+			gitIn("add", "list.txt")
+		}
+
+		m := &models.Model{}
+		p := NewHunkPanel(root, []string{"list.txt"}, staged, nil)
+		p.Update(p.Load()(), m)
+		if len(p.hunks) != 1 {
+			t.Fatalf("staged=%v: expected 1 hunk, got %d", staged, len(p.hunks))
+		}
+
+		p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}, m)
+		if len(p.hunks) != 3 {
+			t.Fatalf("staged=%v: expected 3 pieces after splitting, got %d", staged, len(p.hunks))
+		}
+
+		apply := p.Update(tea.KeyMsg{Type: tea.KeyEnter}, m)
+		if apply == nil {
+			t.Fatalf("staged=%v: applying the first piece did nothing: %s", staged, p.err)
+		}
+		p.Update(apply(), m)
+		p.Update(p.Load()(), m)
+		if p.err != "" {
+			t.Fatalf("staged=%v: %s", staged, p.err)
+		}
+		if len(p.hunks) != 2 {
+			t.Errorf("staged=%v: expected the 2 remaining pieces to stay split, got %d hunk(s)", staged, len(p.hunks))
+		}
+	}
+}
@@ -0,0 +1,507 @@
+package panels
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/ui"
+	"gemini-orchestrator/internal/utils"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// issuePrompt replaces the two stages of auto_issue.zsh, turning the
+// request into a command and then parsing its intent, with one typed answer
+const issuePrompt = `You turn a request about GitHub issues into exactly one issue operation. Respond with only a JSON object and no other text:
+{"operation": "close", "number": 12, "body": "Fixed by #14.", "reason": "completed"}
+
+Operations and the fields they take:
+- create: title (required, one line), body, add_labels, add_assignees
+- edit: number, and any of title, body, add_labels, remove_labels, add_assignees, remove_assignees
+- comment: number, body (the comment, required)
+- view: number
+- close: number, body (an optional closing comment), reason ("completed" or "not planned")
+- reopen: number, body (an optional comment)
+- label: number, add_labels and/or remove_labels
+- assign: number, add_assignees and/or remove_assignees; "@me" is the user
+
+Leave out fields the operation does not take. Only use labels from the repository's labels, mapping requested ones to the closest existing label (e.g. "urgent" to "priority:high"). "This issue" or "the current issue" means the issue the current branch works on. Only use information from the request and the issues shown; do not invent project details. Write titles and bodies in a clear, professional tone, with markdown in bodies. When a body edit appends or prepends text, include the existing body.`
+
+// Bounds on what an issue request sends along
+const (
+	maxReferencedIssues = 3
+	maxIssueBody        = 8000
+)
+
+// issueMention matches "#12" in a request
+var issueMention = regexp.MustCompile(`#(\d+)\b`)
+
+// IssueInput is a natural-language request about issues
+type IssueInput struct {
+	Request string
+	Context string // GEMINI.md context
+	Issue   int    // Issue the current branch works on, 0 when none
+	DryRun  bool   // Show what would be done instead of doing it
+}
+
+type issueMode int
+
+const (
+	issueLoading issueMode = iota
+	issueGenerating
+	issueChecking
+	issueConfirm
+	issueFeedback
+	issueRunning
+)
+
+// issueLoadedMsg carries the repository's labels and the issues the
+// request mentions. Either may be missing; the request is answered anyway.
+type issueLoadedMsg struct {
+	labels []string
+	issues []*github.Issue
+}
+
+// issueTargetMsg carries the issue an operation applies to
+type issueTargetMsg struct {
+	issue *github.Issue
+	err   error
+}
+
+// issueDoneMsg reports an executed operation
+type issueDoneMsg struct {
+	url   string
+	issue *github.Issue // The viewed issue
+	err   error
+}
+
+// IssuePanel has Gemini turn a request into a github.Operation, shows it
+// on a card to confirm and runs it with gh
+type IssuePanel struct {
+	input    IssueInput
+	mode     issueMode
+	labels   []string
+	issues   []*github.Issue
+	stream   *llm.Stream
+	reply    string
+	feedback []string
+	op       *github.Operation
+	target   *github.Issue
+	question textinput.Model
+	err      string
+}
+
+// NewIssuePanel prepares the panel; Load gathers the context
+func NewIssuePanel(input IssueInput) *IssuePanel {
+	p := &IssuePanel{input: input}
+	p.question = newLineInput("e.g. use the bug label, close as not planned", 500)
+	return p
+}
+
+// Load fetches the repository's labels and the issues the request
+// mentions, or the branch's issue, in the background
+func (p *IssuePanel) Load() tea.Cmd {
+	var numbers []int
+	if p.input.Issue != 0 {
+		numbers = append(numbers, p.input.Issue)
+	}
+	for _, match := range issueMention.FindAllStringSubmatch(p.input.Request, -1) {
+		number, _ := strconv.Atoi(match[1])
+		if !slices.Contains(numbers, number) {
+			numbers = append(numbers, number)
+		}
+	}
+	numbers = numbers[:min(len(numbers), maxReferencedIssues)]
+
+	return func() tea.Msg {
+		var msg issueLoadedMsg
+		msg.labels, _ = github.ListLabels()
+		for _, number := range numbers {
+			// A mention may be a pull request; the model is told what exists
+			if issue, err := github.ViewIssue(number); err == nil {
+				msg.issues = append(msg.issues, issue)
+			}
+		}
+		return msg
+	}
+}
+
+// Generate streams the operation from Gemini
+func (p *IssuePanel) Generate(m *models.Model) tea.Cmd {
+	backend, model, err := m.LLM()
+	if err != nil {
+		p.mode, p.err = issueConfirm, err.Error()
+		return nil
+	}
+	p.mode, p.reply, p.op, p.target, p.err = issueGenerating, "", nil, nil, ""
+	p.stream = backend.Stream(p.request(model))
+	return p.stream.Next()
+}
+
+// request puts the context, labels and issues in the input and the
+// request itself in the prompt
+func (p *IssuePanel) request(model string) llm.Request {
+	var input strings.Builder
+	if p.input.Context != "" {
+		fmt.Fprintf(&input, "Repository context from GEMINI.md:\n%s\n\n", p.input.Context)
+	}
+	if len(p.labels) > 0 {
+		fmt.Fprintf(&input, "Repository labels: %s\n\n", strings.Join(p.labels, ", "))
+	}
+	if p.input.Issue != 0 {
+		fmt.Fprintf(&input, "The current branch works on issue #%d.\n\n", p.input.Issue)
+	}
+	for _, issue := range p.issues {
+		body := issue.Body
+		if len(body) > maxIssueBody {
+			body = utils.TruncateBytes(body, maxIssueBody) + "\n[truncated]"
+		}
+		fmt.Fprintf(&input, "Issue #%d (%s): %s\nLabels: %s\nAssignees: %s\nBody:\n%s\n\n",
+			issue.Number, issue.State, issue.Title, labelNames(issue), assigneeNames(issue), body)
+	}
+
+	prompt := issuePrompt + "\n\nRequest: " + p.input.Request
+	if len(p.feedback) > 0 {
+		prompt += "\n\nAdditional feedback to consider: " + strings.Join(p.feedback, "; ")
+	}
+	return llm.Request{Model: model, Prompt: prompt, Input: input.String()}
+}
+
+func (p *IssuePanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case issueLoadedMsg:
+		p.labels, p.issues = msg.labels, msg.issues
+		return p.Generate(m)
+	case llm.ChunkMsg:
+		if msg.Stream != p.stream {
+			return nil
+		}
+		p.reply += msg.Text
+		return p.stream.Next()
+	case llm.DoneMsg:
+		if msg.Stream != p.stream {
+			return nil
+		}
+		return p.finishGenerating(msg.Err, m)
+	case issueTargetMsg:
+		if msg.err != nil {
+			p.mode, p.op, p.err = issueConfirm, nil, msg.err.Error()
+			return nil
+		}
+		p.target = msg.issue
+		return p.confirm(m)
+	case issueDoneMsg:
+		return p.finish(msg, m)
+	case tea.KeyMsg:
+		switch p.mode {
+		case issueLoading, issueChecking:
+			if msg.String() == "esc" {
+				m.AddResult("Issue operation cancelled")
+				m.Panel = nil
+			}
+		case issueGenerating:
+			if msg.String() == "esc" {
+				p.stream.Cancel()
+			}
+		case issueConfirm:
+			return p.updateConfirm(msg, m)
+		case issueFeedback:
+			return p.updateFeedback(msg, m)
+		}
+	}
+	return nil
+}
+
+// finishGenerating parses and validates the operation, then looks up the
+// issue it applies to
+func (p *IssuePanel) finishGenerating(err error, m *models.Model) tea.Cmd {
+	p.stream = nil
+	switch {
+	case errors.Is(err, llm.ErrCancelled):
+		m.AddResult("Issue operation cancelled")
+		m.Panel = nil
+		return nil
+	case err != nil:
+		p.mode, p.err = issueConfirm, err.Error()
+		return nil
+	}
+
+	op, err := github.ParseOperation(p.reply)
+	if err == nil {
+		err = p.checkLabels(op)
+	}
+	if err != nil {
+		p.mode, p.err = issueConfirm, err.Error()
+		return nil
+	}
+	if op.Kind == github.OperationCreate && !strings.Contains(op.Body, geminiAttribution) {
+		op.Body = strings.TrimSpace(op.Body + "\n\n" + geminiAttribution)
+	}
+	p.op = op
+
+	if op.Number == 0 {
+		return p.confirm(m)
+	}
+	for _, issue := range p.issues {
+		if issue.Number == op.Number {
+			p.target = issue
+			return p.confirm(m)
+		}
+	}
+	p.mode = issueChecking
+	return func() tea.Msg {
+		issue, err := github.ViewIssue(op.Number)
+		return issueTargetMsg{issue: issue, err: err}
+	}
+}
+
+// checkLabels rejects labels the repository does not have, when its
+// labels are known, rather than letting gh fail halfway
+func (p *IssuePanel) checkLabels(op *github.Operation) error {
+	if len(p.labels) == 0 {
+		return nil
+	}
+	for _, label := range op.AddLabels {
+		if !slices.ContainsFunc(p.labels, func(name string) bool { return strings.EqualFold(name, label) }) {
+			return fmt.Errorf("the repository has no label %q", label)
+		}
+	}
+	return nil
+}
+
+// confirm shows the card, except for view, which changes nothing and runs
+// straight away
+func (p *IssuePanel) confirm(m *models.Model) tea.Cmd {
+	if !p.op.Changes() {
+		return p.execute(m)
+	}
+	p.mode = issueConfirm
+	return nil
+}
+
+func (p *IssuePanel) updateConfirm(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "enter", "y":
+		if p.op != nil {
+			return p.execute(m)
+		}
+	case "r":
+		p.mode, p.err = issueFeedback, ""
+		p.question.SetValue("")
+		return p.question.Focus()
+	case "esc", "n", "q":
+		m.AddResult("Issue operation cancelled")
+		m.Panel = nil
+	}
+	return nil
+}
+
+func (p *IssuePanel) updateFeedback(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		if feedback := strings.TrimSpace(p.question.Value()); feedback != "" {
+			p.feedback = append(p.feedback, feedback)
+		}
+		p.question.Blur()
+		return p.Generate(m)
+	case "esc":
+		p.mode = issueConfirm
+		p.question.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.question, cmd = p.question.Update(msg)
+	return cmd
+}
+
+// execute runs the operation with gh; a dry run only reports changes
+func (p *IssuePanel) execute(m *models.Model) tea.Cmd {
+	op := p.op
+	if p.input.DryRun && op.Changes() {
+		m.AddResult("🔍 Dry run: would " + strings.ToLower(op.Summary()[:1]) + op.Summary()[1:])
+		m.Panel = nil
+		return nil
+	}
+
+	p.mode, p.err = issueRunning, ""
+	if !op.Changes() {
+		target := p.target
+		return func() tea.Msg {
+			return issueDoneMsg{issue: target}
+		}
+	}
+	return func() tea.Msg {
+		url, err := op.Execute()
+		return issueDoneMsg{url: url, err: err}
+	}
+}
+
+// finish adds the outcome to history and closes the panel
+func (p *IssuePanel) finish(msg issueDoneMsg, m *models.Model) tea.Cmd {
+	if msg.err != nil {
+		p.mode, p.err = issueConfirm, msg.err.Error()
+		return nil
+	}
+	m.Panel = nil
+
+	op := p.op
+	if msg.issue != nil {
+		m.AddResponse(formatIssue(msg.issue))
+		return nil
+	}
+	url := msg.url
+	if url == "" && p.target != nil {
+		url = p.target.URL
+	}
+
+	var result string
+	switch op.Kind {
+	case github.OperationCreate:
+		result = "Created issue"
+	case github.OperationEdit:
+		result = fmt.Sprintf("Edited #%d", op.Number)
+	case github.OperationComment:
+		result = fmt.Sprintf("Commented on #%d", op.Number)
+	case github.OperationClose:
+		result = fmt.Sprintf("Closed #%d", op.Number)
+	case github.OperationReopen:
+		result = fmt.Sprintf("Reopened #%d", op.Number)
+	case github.OperationLabel:
+		result = fmt.Sprintf("Updated the labels of #%d", op.Number)
+	case github.OperationAssign:
+		result = fmt.Sprintf("Updated the assignees of #%d", op.Number)
+	}
+	if url != "" {
+		result += " " + ui.Hyperlink(url, url)
+	}
+	m.AddResult(result)
+	return nil
+}
+
+// formatIssue renders a viewed issue for history
+func formatIssue(issue *github.Issue) string {
+	text := fmt.Sprintf("#%d %s (%s)\n", issue.Number, issue.Title, issue.State)
+	if labels := labelNames(issue); labels != "" {
+		text += "Labels: " + labels + "\n"
+	}
+	if assignees := assigneeNames(issue); assignees != "" {
+		text += "Assignees: " + assignees + "\n"
+	}
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		text += "\n" + body + "\n"
+	}
+	return text + "\n" + ui.Hyperlink(issue.URL, issue.URL)
+}
+
+func labelNames(issue *github.Issue) string {
+	names := make([]string, len(issue.Labels))
+	for i, label := range issue.Labels {
+		names[i] = label.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func assigneeNames(issue *github.Issue) string {
+	names := make([]string, len(issue.Assignees))
+	for i, user := range issue.Assignees {
+		names[i] = user.Login
+	}
+	return strings.Join(names, ", ")
+}
+
+func (p *IssuePanel) View(m models.Model) string {
+	var view string
+
+	details := ""
+	if p.input.DryRun {
+		details = "  dry run"
+	}
+	view += ui.SuggestionStyle.Render("Issue: "+truncate(p.input.Request, max(20, m.Width-20))+ui.BlurredStyle.Render(details)) + "\n\n"
+
+	switch p.mode {
+	case issueLoading:
+		view += ui.SuggestionStyle.Render("Fetching labels and issues…") + "\n\n"
+		return view + keyHints("esc cancel")
+	case issueGenerating:
+		view += ui.SuggestionStyle.Render("✦ Gemini is reading the request…") + "\n"
+		lines := strings.Split(strings.TrimSpace(p.reply), "\n")
+		for _, line := range lines[max(0, len(lines)-listHeight(m.Height, 12)):] {
+			view += "  " + ui.BlurredStyle.Render(truncate(line, max(20, m.Width-4))) + "\n"
+		}
+		return view + "\n" + keyHints("esc cancel")
+	case issueChecking:
+		view += ui.SuggestionStyle.Render(fmt.Sprintf("Fetching #%d…", p.op.Number)) + "\n\n"
+		return view + keyHints("esc cancel")
+	}
+
+	if p.op != nil {
+		view += p.viewCard(m) + "\n"
+	}
+	switch p.mode {
+	case issueFeedback:
+		view += ui.SuggestionStyle.Render("Feedback: ") + p.question.View() + "\n\n"
+		view += keyHints("enter regenerate", "esc back")
+	case issueRunning:
+		view += ui.SuggestionStyle.Render("Running gh…") + "\n"
+	default:
+		if p.err != "" {
+			view += ui.WarningStyle.Render("❌ "+p.err) + "\n\n"
+		}
+		if p.op != nil {
+			view += keyHints("enter run", "r regenerate with feedback", "esc cancel")
+		} else {
+			view += keyHints("r regenerate with feedback", "esc cancel")
+		}
+	}
+	return view
+}
+
+// viewCard renders the operation with every field it sets
+func (p *IssuePanel) viewCard(m models.Model) string {
+	op := p.op
+	width := max(30, m.Width-8)
+
+	lines := []string{ui.FocusedStyle.Bold(true).Render(truncate(op.Summary(), width))}
+	field := func(name, value string) {
+		if value != "" {
+			lines = append(lines, ui.BlurredStyle.Render(fmt.Sprintf("%-17s", name))+truncate(value, width-17))
+		}
+	}
+	if p.target != nil {
+		field("Issue", fmt.Sprintf("#%d %s (%s)", p.target.Number, p.target.Title, p.target.State))
+	}
+	if op.Kind != github.OperationCreate {
+		field("Title", op.Title)
+	}
+	field("Reason", op.Reason)
+	field("Add labels", strings.Join(op.AddLabels, ", "))
+	field("Remove labels", strings.Join(op.RemoveLabels, ", "))
+	field("Add assignees", strings.Join(op.AddAssignees, ", "))
+	field("Remove assignees", strings.Join(op.RemoveAssignees, ", "))
+
+	if op.Body != "" {
+		name := "Body"
+		if op.Kind == github.OperationComment || op.Kind == github.OperationClose || op.Kind == github.OperationReopen {
+			name = "Comment"
+		}
+		lines = append(lines, "", ui.BlurredStyle.Render(name+":"))
+		body := strings.Split(op.Body, "\n")
+		shown := listHeight(m.Height, 20)
+		for _, line := range body[:min(len(body), shown)] {
+			lines = append(lines, truncate(line, width))
+		}
+		if len(body) > shown {
+			lines = append(lines, ui.BlurredStyle.Render(fmt.Sprintf("…%d more lines", len(body)-shown)))
+		}
+	}
+	return ui.CardStyle.Render(strings.Join(lines, "\n")) + "\n"
+}
@@ -0,0 +1,477 @@
+package panels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/ui"
+	"gemini-orchestrator/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// explainPrompt asks Gemini to explain the commit given on standard input
+const explainPrompt = "Explain the git commit provided on standard input to a developer reading the project history. " +
+	"Start with one or two sentences on what it changes and why, then list the notable changes. " +
+	"Be concise and answer in plain text without Markdown headings."
+
+// maxExplainInput bounds the commit text sent to Gemini
+const maxExplainInput = 60000
+
+// logLoadedMsg carries reloaded commits
+type logLoadedMsg struct {
+	commits []git.Commit
+	err     error
+}
+
+// logDetailMsg carries the message and diff of the commit to show
+type logDetailMsg struct {
+	commit  git.Commit
+	message string
+	files   []git.FileDiff
+	err     error
+}
+
+// logExplainMsg carries Gemini's explanation of a commit
+type logExplainMsg struct {
+	commit      git.Commit
+	explanation string
+	err         error
+}
+
+// logCopiedMsg reports a copied commit hash; viaTerminal means there was
+// no system clipboard and the terminal is asked to copy instead
+type logCopiedMsg struct {
+	hash        string
+	viaTerminal bool
+}
+
+// logRow is a commit or a graph-only line between commits
+type logRow struct {
+	commit int // Index into commits, -1 for graph-only lines
+	graph  string
+}
+
+// LogPanel browses commits with their graph and opens a commit's message
+// and diff in a detail pane, from which it can be explained by Gemini or
+// its hash copied
+type LogPanel struct {
+	root         string
+	options      git.LogOptions
+	title        string
+	commits      []git.Commit
+	selected     int
+	detail       *DiffPanel  // Open commit, nil while browsing the list
+	detailCommit *git.Commit // Commit shown in detail
+	detailLines  []string    // Header of the detail pane before any explanation
+	explanations map[string]string
+	explaining   map[string]bool
+	loading      bool // The first listing is still running
+	message      string
+	err          string
+}
+
+// NewLogPanel lists the result of git.Log with options in root once Load
+// has run it; an empty or failed first listing closes the panel. title
+// describes the range shown.
+func NewLogPanel(root string, options git.LogOptions, title string) *LogPanel {
+	return &LogPanel{
+		root:         root,
+		options:      options,
+		title:        title,
+		loading:      true,
+		explanations: map[string]string{},
+		explaining:   map[string]bool{},
+	}
+}
+
+// Load lists the commits again
+func (p *LogPanel) Load() tea.Cmd {
+	root, options := p.root, p.options
+	return func() tea.Msg {
+		commits, err := git.Log(root, options)
+		return logLoadedMsg{commits: commits, err: err}
+	}
+}
+
+func (p *LogPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case logLoadedMsg:
+		if p.loading {
+			p.loading = false
+			switch {
+			case msg.err != nil:
+				m.AddResult(fmt.Sprintf("❌ %v", msg.err))
+				m.Panel = nil
+				return nil
+			case len(msg.commits) == 0:
+				m.AddResult("No commits")
+				m.Panel = nil
+				return nil
+			}
+		}
+		p.message, p.err = "", ""
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		// Keep the selected commit when it is still listed
+		selected := p.selectedCommit()
+		p.commits = msg.commits
+		p.selected = min(p.selected, max(0, len(p.commits)-1))
+		for i, c := range p.commits {
+			if selected != nil && c.Hash == selected.Hash {
+				p.selected = i
+			}
+		}
+		return nil
+	case logDetailMsg:
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return nil
+		}
+		p.openDetail(msg, m)
+		return nil
+	case logExplainMsg:
+		delete(p.explaining, msg.commit.Hash)
+		p.setMessage("", "")
+		if msg.err != nil {
+			p.setMessage("", msg.err.Error())
+			return nil
+		}
+		p.explanations[msg.commit.Hash] = msg.explanation
+		p.showExplanation(m.Width)
+
+		// Keep the explanation in the conversation after the panel closes
+		m.Messages = append(m.Messages, fmt.Sprintf("Explain %s %s", msg.commit.ShortHash, msg.commit.Subject))
+		m.AddResult(msg.explanation)
+		return nil
+	case logCopiedMsg:
+		if msg.viaTerminal {
+			p.setMessage("✓ Sent "+msg.hash+" to the terminal clipboard (OSC 52)", "")
+			// Printed by the program so it cannot interleave with a frame
+			return tea.Printf("%s", utils.TerminalCopySequence(msg.hash))
+		}
+		p.setMessage("✓ Copied "+msg.hash+" to the clipboard", "")
+		return nil
+	case tea.KeyMsg:
+		if p.detail != nil {
+			return p.updateDetail(msg, m)
+		}
+		return p.updateList(msg, m)
+	}
+
+	// Results of the detail pane's own work, such as a reload
+	if p.detail != nil {
+		return p.detail.Update(msg, m)
+	}
+	return nil
+}
+
+func (p *LogPanel) updateList(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	page := listHeight(m.Height, 11)
+	switch msg.String() {
+	case "up", "k":
+		p.selected = max(0, p.selected-1)
+	case "down", "j":
+		p.selected = min(len(p.commits)-1, p.selected+1)
+	case "pgup", "b":
+		p.selected = max(0, p.selected-page)
+	case "pgdown", " ":
+		p.selected = max(0, min(len(p.commits)-1, p.selected+page))
+	case "home", "g":
+		p.selected = 0
+	case "end", "G":
+		p.selected = max(0, len(p.commits)-1)
+	case "enter", "right", "l":
+		if commit := p.selectedCommit(); commit != nil {
+			return p.loadDetail(*commit)
+		}
+	case "e":
+		if commit := p.selectedCommit(); commit != nil {
+			return p.explain(*commit, m)
+		}
+	case "y":
+		if commit := p.selectedCommit(); commit != nil {
+			return copyHash(commit.Hash)
+		}
+	case "r":
+		return p.Load()
+	case "esc", "q":
+		m.Panel = nil
+	}
+	return nil
+}
+
+func (p *LogPanel) updateDetail(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	// The file jump list handles its own Esc
+	if p.detail.showFiles {
+		return p.detail.Update(msg, m)
+	}
+	switch msg.String() {
+	case "e":
+		return p.explain(*p.detailCommit, m)
+	case "y":
+		return copyHash(p.detailCommit.Hash)
+	case "esc", "q", "left", "h":
+		p.detail, p.detailCommit = nil, nil
+		return nil
+	}
+	return p.detail.Update(msg, m)
+}
+
+func (p *LogPanel) selectedCommit() *git.Commit {
+	if p.selected < 0 || p.selected >= len(p.commits) {
+		return nil
+	}
+	return &p.commits[p.selected]
+}
+
+// setMessage shows a status line in whichever pane is visible
+func (p *LogPanel) setMessage(message, err string) {
+	p.message, p.err = message, err
+	if p.detail != nil {
+		p.detail.message, p.detail.err = message, err
+	}
+}
+
+func (p *LogPanel) loadDetail(commit git.Commit) tea.Cmd {
+	root := p.root
+	return func() tea.Msg {
+		message, err := git.CommitMessage(root, commit.Hash)
+		if err != nil {
+			return logDetailMsg{err: err}
+		}
+		files, err := git.Diff(root, git.DiffOptions{Commit: commit.Hash})
+		return logDetailMsg{commit: commit, message: message, files: files, err: err}
+	}
+}
+
+func (p *LogPanel) openDetail(msg logDetailMsg, m *models.Model) {
+	commit := msg.commit
+	header := fmt.Sprintf("commit %s", commit.Hash)
+	if commit.IsMerge() {
+		header += "  (merge, changes against the first parent)"
+	}
+	lines := []string{
+		header,
+		fmt.Sprintf("Author: %s <%s>", commit.Author, commit.Email),
+		fmt.Sprintf("Date:   %s (%s)", commit.Date.Format("Mon Jan 2 15:04:05 2006 -0700"), relativeTime(commit.Date, time.Now())),
+		"",
+	}
+	for _, line := range strings.Split(msg.message, "\n") {
+		lines = append(lines, "    "+line)
+	}
+	lines = append(lines, "")
+
+	p.detail = newDiffPanel(p.root, git.DiffOptions{Commit: commit.Hash}, fmt.Sprintf("%s %s", commit.ShortHash, commit.Subject), msg.files)
+	p.detail.actions = []string{"e explain", "y copy SHA"}
+	p.detail.embedded = true
+	p.detailCommit = &commit
+	p.detailLines = lines
+	p.showExplanation(m.Width)
+	p.setMessage("", "")
+	if p.explaining[commit.Hash] {
+		p.setMessage("Asking Gemini to explain "+commit.ShortHash+"…", "")
+	}
+}
+
+// showExplanation adds the explanation of the open commit, if there is
+// one, below its message
+func (p *LogPanel) showExplanation(width int) {
+	if p.detail == nil {
+		return
+	}
+	lines := append([]string{}, p.detailLines...)
+	if explanation, ok := p.explanations[p.detailCommit.Hash]; ok {
+		wrapped := lipgloss.NewStyle().Width(max(20, width-8)).Render(explanation)
+		lines = append(lines, "Explanation (Gemini):")
+		for _, line := range strings.Split(wrapped, "\n") {
+			lines = append(lines, "    "+strings.TrimRight(line, " "))
+		}
+		lines = append(lines, "")
+	}
+	p.detail.setHeader(lines)
+}
+
+// explain asks Gemini about a commit in the background
+func (p *LogPanel) explain(commit git.Commit, m *models.Model) tea.Cmd {
+	if _, ok := p.explanations[commit.Hash]; ok {
+		if p.detail != nil {
+			p.setMessage("The explanation is shown below the commit message", "")
+		} else {
+			p.setMessage("Already explained, press enter to read it", "")
+		}
+		return nil
+	}
+	if p.explaining[commit.Hash] {
+		return nil
+	}
+	backend, model, err := m.LLM()
+	if err != nil {
+		p.setMessage("", err.Error())
+		return nil
+	}
+	p.explaining[commit.Hash] = true
+	p.setMessage("Asking Gemini to explain "+commit.ShortHash+"…", "")
+
+	root := p.root
+	return func() tea.Msg {
+		patch, err := git.CommitPatch(root, commit.Hash)
+		if err != nil {
+			return logExplainMsg{commit: commit, err: err}
+		}
+		if len(patch) > maxExplainInput {
+			patch = utils.TruncateBytes(patch, maxExplainInput) + "\n[diff truncated]\n"
+		}
+		explanation, err := backend.Generate(context.Background(), llm.Request{Model: model, Prompt: explainPrompt, Input: patch})
+		return logExplainMsg{commit: commit, explanation: explanation, err: err}
+	}
+}
+
+func copyHash(hash string) tea.Cmd {
+	return func() tea.Msg {
+		err := utils.CopyToClipboard(hash)
+		return logCopiedMsg{hash: hash, viaTerminal: err != nil}
+	}
+}
+
+func (p *LogPanel) rows() []logRow {
+	var rows []logRow
+	for i, c := range p.commits {
+		rows = append(rows, logRow{commit: i, graph: c.Graph})
+		for _, edge := range c.Edges {
+			rows = append(rows, logRow{commit: -1, graph: edge})
+		}
+	}
+	return rows
+}
+
+func (p *LogPanel) View(m models.Model) string {
+	if p.detail != nil {
+		return p.detail.View(m)
+	}
+
+	var view string
+	title := "Log  " + p.title
+	if len(p.commits) > 0 {
+		title += ui.BlurredStyle.Render(fmt.Sprintf("  commit %d/%d", p.selected+1, len(p.commits)))
+	}
+	view += ui.SuggestionStyle.Render(title) + "\n\n"
+
+	rows := p.rows()
+	if p.loading {
+		view += ui.SuggestionStyle.Render("Loading commits…") + "\n"
+	} else if len(p.commits) == 0 && p.err == "" {
+		view += ui.SuggestionStyle.Render("No commits") + "\n"
+	}
+
+	// Scroll by screen row so graph-only lines stay in view
+	selectedRow := 0
+	for i, row := range rows {
+		if row.commit == p.selected {
+			selectedRow = i
+		}
+	}
+	width := m.Width
+	if width <= 0 {
+		width = 80
+	}
+	now := time.Now()
+	start, end := visibleRange(selectedRow, len(rows), listHeight(m.Height, 11))
+	for _, row := range rows[start:end] {
+		if row.commit < 0 {
+			view += "  " + ui.BlurredStyle.Render("  "+row.graph) + "\n"
+			continue
+		}
+		view += p.renderCommit(row.commit, now, width-2) + "\n"
+	}
+	view += "\n"
+
+	if p.err != "" {
+		view += ui.WarningStyle.Render("❌ "+p.err) + "\n"
+	} else if p.message != "" {
+		view += ui.SuggestionStyle.Render(p.message) + "\n"
+	}
+
+	view += keyHints("↑/↓ select", "enter details", "e explain", "y copy SHA", "r reload", "esc close")
+	return view
+}
+
+// renderCommit draws "graph hash [type] subject … author, age" in width columns
+func (p *LogPanel) renderCommit(index int, now time.Time, width int) string {
+	c := p.commits[index]
+	cursor := "  "
+	if index == p.selected {
+		cursor = "> "
+	}
+
+	// The badge replaces the type prefix of Conventional Commits subjects
+	subject, badge, badgeWidth := c.Subject, "", 0
+	if conventional, ok := c.Conventional(); ok {
+		label := conventional.Type
+		if conventional.Breaking {
+			label += "!"
+		}
+		badge = ui.CommitTypeStyle(conventional.Type).Render("["+label+"]") + " "
+		badgeWidth = len(label) + 3
+		subject = conventional.Description
+		if conventional.Scope != "" {
+			subject = conventional.Scope + ": " + subject
+		}
+	}
+
+	meta := fmt.Sprintf("  %s, %s", c.Author, relativeTime(c.Date, now))
+	fixed := len(cursor) + lipgloss.Width(c.Graph) + len(c.ShortHash) + 1 + badgeWidth
+	subjectWidth := width - fixed - lipgloss.Width(meta)
+	if subjectWidth < 20 {
+		// Narrow terminals drop the author and date before the subject
+		meta = ""
+		subjectWidth = width - fixed
+	}
+	subject = truncate(subject, max(10, subjectWidth))
+	padding := strings.Repeat(" ", max(0, subjectWidth-lipgloss.Width(subject)))
+
+	subjectStyle := ui.MessageStyle
+	if index == p.selected {
+		subjectStyle = ui.SelectedSuggestionStyle.Padding(0)
+	}
+	return "  " + ui.SelectedSuggestionStyle.Padding(0).Render(cursor) +
+		ui.BlurredStyle.Render(c.Graph) +
+		ui.CommitHashStyle.Render(c.ShortHash) + " " +
+		badge +
+		subjectStyle.Render(subject) + padding +
+		ui.BlurredStyle.Render(meta)
+}
+
+// relativeTime describes how long ago t was, like "3 days ago"
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return ago(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return ago(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return ago(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return ago(int(d/(30*24*time.Hour)), "month")
+	default:
+		return ago(int(d/(365*24*time.Hour)), "year")
+	}
+}
+
+func ago(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit + " ago"
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
@@ -0,0 +1,65 @@
+package panels
+
+import (
+	"strings"
+
+	"gemini-orchestrator/internal/ui"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// visibleRange returns the slice bounds of a list scrolled so that selected
+// stays on screen when only height rows fit
+func visibleRange(selected, total, height int) (int, int) {
+	if height <= 0 || total <= height {
+		return 0, total
+	}
+	start := selected - height/2
+	start = max(0, min(start, total-height))
+	return start, start + height
+}
+
+// listHeight returns how many list rows fit once chrome lines are subtracted
+func listHeight(termHeight, chrome int) int {
+	return max(3, termHeight-chrome)
+}
+
+// truncate shortens s to width columns, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// keyHints renders the shortcut line at the bottom of a panel
+func keyHints(hints ...string) string {
+	return ui.BlurredStyle.Render("  "+strings.Join(hints, " • ")) + "\n"
+}
+
+// newEditor returns a multi-line editor for text Gemini wrote, such as a
+// commit message, without a length limit
+func newEditor() textarea.Model {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+	editor.MaxHeight = 0
+	editor.Cursor.SetMode(cursor.CursorStatic)
+	return editor
+}
+
+// newLineInput returns a single-line field for a form, without a prompt
+func newLineInput(placeholder string, limit int) textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = placeholder
+	input.CharLimit = limit
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
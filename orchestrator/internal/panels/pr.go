@@ -0,0 +1,565 @@
+package panels
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/llm"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/sanitize"
+	"gemini-orchestrator/internal/ui"
+	"gemini-orchestrator/internal/utils"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prFormat is the answer format both PR prompts ask for, parsed with
+// sanitize.ParseTitleBody
+const prFormat = `Format your response exactly like this, with nothing before TITLE: and nothing after the body:
+TITLE: Fix: Resolve login timeout issue
+BODY: ## Summary
+- Fixed session timeout handling
+
+## Changes
+- Modified auth.js timeout logic
+- Added better error handling
+
+Closes #123
+
+` + geminiAttribution
+
+// prPrompt and prUpdatePrompt follow generate_pr_content and
+// generate_pr_update_content in utils/generators/pr_content_generator.zsh
+const (
+	prPrompt = `Based on the commit history above, write the title and body of a pull request. Ensure to include any relevant issue references (e.g., 'resolves #123', 'closes #456', 'fixes #789', 'connects to #101', 'relates to #202', 'contributes to #303') found in the commit messages.
+
+Structure the body with:
+- ## Summary (brief overview of changes)
+- ## Changes (bullet points of specific modifications)
+- Issue references if applicable
+
+Always end the body with the attribution line:
+` + geminiAttribution + "\n\n" + prFormat
+
+	prUpdatePrompt = `You are updating an existing PR. Based on ALL the commits for this PR and the existing PR content above, generate an updated title and body for the PR.
+
+Instructions:
+- Look at ALL commits for this PR to understand the full scope
+- Generate an updated title that builds upon or refines the existing one
+- Create an updated body that extends the existing content with any new information from recent commits
+- Maintain consistency with the existing structure and tone
+- Include any new issue references found in recent commits
+
+Always end the body with the attribution line:
+` + geminiAttribution + "\n\n" + prFormat
+)
+
+// maxPRCommits bounds the commit history sent to Gemini
+const maxPRCommits = 60000
+
+// PRInput is what the pull request panel starts from; commits and the
+// existing pull request are looked up by Load
+type PRInput struct {
+	Root    string
+	Branch  string
+	Context string // GEMINI.md context
+	Extra   string // Context typed after /pr
+	Issue   int    // Issue the branch was started for, 0 when none
+	DryRun  bool   // Show what would be done instead of doing it
+}
+
+type prMode int
+
+const (
+	prLoading prMode = iota
+	prGenerating
+	prReview
+	prFeedback
+	prSubmitting
+)
+
+// Fields of the review form, in tab order
+const (
+	prFieldTitle = iota
+	prFieldBody
+	prFieldReviewers
+	prFieldLabels
+	prFieldCount
+)
+
+// prLoadedMsg carries the base branch, the commits a pull request would
+// contain and the open pull request to update, if any
+type prLoadedMsg struct {
+	base     string
+	commits  []git.Commit
+	details  string // Subjects and bodies for the prompt
+	stat     string // git diff --stat against the base
+	existing *github.PullRequest
+	err      error
+}
+
+// prSubmittedMsg reports the created or updated pull request
+type prSubmittedMsg struct {
+	url string
+	err error
+}
+
+// PRPanel creates a pull request for the current branch, or updates the
+// open one, with a title and body written by Gemini and edited in place
+// before anything is pushed
+type PRPanel struct {
+	input     PRInput
+	mode      prMode
+	loaded    prLoadedMsg
+	stream    *llm.Stream
+	reply     string
+	feedback  []string
+	focus     int
+	draft     bool
+	title     textinput.Model
+	body      textarea.Model
+	reviewers textinput.Model
+	labels    textinput.Model
+	question  textinput.Model
+	status    string
+	err       string
+}
+
+// NewPRPanel prepares the panel; Load looks up the commits
+func NewPRPanel(input PRInput) *PRPanel {
+	p := &PRPanel{input: input, body: newEditor()}
+	p.title = newLineInput("", 256)
+	p.reviewers = newLineInput("logins or org/team, comma separated", 500)
+	p.labels = newLineInput("comma separated", 500)
+	p.question = newLineInput("e.g. shorter title, mention the migration", 500)
+	return p
+}
+
+// Load finds the base branch, the commits since it and an open pull
+// request for the branch in the background
+func (p *PRPanel) Load() tea.Cmd {
+	root, branch := p.input.Root, p.input.Branch
+	return func() tea.Msg {
+		existing, err := github.OpenPullRequest(branch)
+		if err != nil {
+			return prLoadedMsg{err: err}
+		}
+		var base string
+		if existing != nil {
+			if existing, err = github.ViewPullRequest(existing.Number); err != nil {
+				return prLoadedMsg{err: err}
+			}
+			base = existing.BaseRefName
+		} else if base, err = git.DefaultBranch(root); err != nil {
+			return prLoadedMsg{err: err}
+		}
+		if base == branch {
+			return prLoadedMsg{err: fmt.Errorf("cannot open a pull request from %s into itself, switch to a feature branch", branch)}
+		}
+
+		baseRef := git.BaseRef(root, base)
+		commits, err := git.Log(root, git.LogOptions{Revs: []string{baseRef + "..HEAD"}, Limit: 100})
+		if err != nil {
+			return prLoadedMsg{err: err}
+		}
+		if len(commits) == 0 {
+			return prLoadedMsg{err: fmt.Errorf("no commits between %s and %s", base, branch)}
+		}
+		details, err := git.CommitDetails(root, baseRef+"..HEAD")
+		if err != nil {
+			return prLoadedMsg{err: err}
+		}
+		stat, err := git.DiffStat(root, baseRef+"...HEAD", 100)
+		if err != nil {
+			return prLoadedMsg{err: err}
+		}
+		return prLoadedMsg{base: base, commits: commits, details: details, stat: stat, existing: existing}
+	}
+}
+
+// Generate streams a title and body from Gemini
+func (p *PRPanel) Generate(m *models.Model) tea.Cmd {
+	backend, model, err := m.LLM()
+	if err != nil {
+		p.mode, p.err = prReview, err.Error()
+		return nil
+	}
+	p.mode, p.reply, p.status, p.err = prGenerating, "", "", ""
+	p.stream = backend.Stream(p.request(model))
+	return p.stream.Next()
+}
+
+// request puts the context, the existing pull request and the commits in
+// the input and the instructions in the prompt
+func (p *PRPanel) request(model string) llm.Request {
+	var input strings.Builder
+	if p.input.Context != "" {
+		fmt.Fprintf(&input, "Repository context from GEMINI.md:\n%s\n\n", p.input.Context)
+	}
+	if existing := p.loaded.existing; existing != nil {
+		fmt.Fprintf(&input, "EXISTING PR CONTENT:\nTitle: %s\nBody: %s\n\n", existing.Title, existing.Body)
+	}
+	details := p.loaded.details
+	if len(details) > maxPRCommits {
+		details = utils.TruncateBytes(details, maxPRCommits) + "\n[history truncated]"
+	}
+	fmt.Fprintf(&input, "Commit history:\n%s\n\nChanged files:\n%s", details, p.loaded.stat)
+
+	prompt := prPrompt
+	if p.loaded.existing != nil {
+		prompt = prUpdatePrompt
+	}
+	if p.input.Extra != "" {
+		prompt = p.input.Extra + " " + prompt
+	}
+	if len(p.feedback) > 0 {
+		prompt += "\n\nAdditional feedback to consider: " + strings.Join(p.feedback, "; ")
+	}
+	return llm.Request{Model: model, Prompt: prompt, Input: input.String()}
+}
+
+func (p *PRPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	switch msg := msg.(type) {
+	case prLoadedMsg:
+		if msg.err != nil {
+			m.AddResult(fmt.Sprintf("❌ %v", msg.err))
+			m.Panel = nil
+			return nil
+		}
+		p.loaded = msg
+		if msg.existing != nil {
+			p.draft = msg.existing.IsDraft
+		}
+		return p.Generate(m)
+	case llm.ChunkMsg:
+		if msg.Stream != p.stream {
+			return nil
+		}
+		p.reply += msg.Text
+		return p.stream.Next()
+	case llm.DoneMsg:
+		if msg.Stream != p.stream {
+			return nil
+		}
+		return p.finishGenerating(msg.Err, m)
+	case prSubmittedMsg:
+		if msg.err != nil {
+			p.mode, p.err = prReview, msg.err.Error()
+			return p.focusField(p.focus)
+		}
+		if existing := p.loaded.existing; existing != nil {
+			m.AddResult(fmt.Sprintf("Updated pull request #%d %s", existing.Number, ui.Hyperlink(msg.url, msg.url)))
+		} else {
+			m.AddResult("Created pull request " + ui.Hyperlink(msg.url, msg.url))
+		}
+		m.Panel = nil
+		return github.LookupPullRequest(p.input.Branch)
+	case tea.KeyMsg:
+		switch p.mode {
+		case prLoading, prSubmitting:
+			if msg.String() == "esc" && p.mode == prLoading {
+				m.AddResult("Pull request cancelled")
+				m.Panel = nil
+			}
+		case prGenerating:
+			if msg.String() == "esc" {
+				p.stream.Cancel()
+			}
+		case prReview:
+			return p.updateReview(msg, m)
+		case prFeedback:
+			return p.updateFeedback(msg, m)
+		}
+	}
+	return nil
+}
+
+// finishGenerating fills the form with the new title and body, keeping
+// the form as it was when generating was cancelled or failed
+func (p *PRPanel) finishGenerating(err error, m *models.Model) tea.Cmd {
+	p.stream = nil
+	p.mode = prReview
+	hasContent := strings.TrimSpace(p.title.Value()) != ""
+
+	switch {
+	case errors.Is(err, llm.ErrCancelled):
+		if !hasContent {
+			m.AddResult("Pull request cancelled")
+			m.Panel = nil
+			return nil
+		}
+		p.status = "Kept the previous title and body"
+		return p.focusField(p.focus)
+	case err != nil:
+		p.err = err.Error()
+		return p.focusField(p.focus)
+	}
+
+	content, err := sanitize.ParseTitleBody(p.reply)
+	if err != nil {
+		p.err = err.Error()
+		return p.focusField(p.focus)
+	}
+	p.title.SetValue(content.Title)
+	p.title.CursorEnd()
+	p.resize(m)
+	p.body.SetValue(github.AppendIssueReference(content.Body, p.input.Issue))
+	return p.focusField(prFieldTitle)
+}
+
+func (p *PRPanel) updateReview(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "tab":
+		return p.focusField((p.focus + 1) % prFieldCount)
+	case "shift+tab":
+		return p.focusField((p.focus + prFieldCount - 1) % prFieldCount)
+	case "ctrl+d":
+		p.draft = !p.draft
+		return nil
+	case "ctrl+r":
+		p.mode, p.status, p.err = prFeedback, "", ""
+		p.blurFields()
+		p.question.SetValue("")
+		return p.question.Focus()
+	case "ctrl+s":
+		return p.submit(m)
+	case "esc":
+		m.AddResult("Pull request cancelled")
+		m.Panel = nil
+		return nil
+	}
+
+	p.err = ""
+	var cmd tea.Cmd
+	switch p.focus {
+	case prFieldTitle:
+		p.title, cmd = p.title.Update(msg)
+	case prFieldBody:
+		p.resize(m)
+		p.body, cmd = p.body.Update(msg)
+	case prFieldReviewers:
+		p.reviewers, cmd = p.reviewers.Update(msg)
+	case prFieldLabels:
+		p.labels, cmd = p.labels.Update(msg)
+	}
+	return cmd
+}
+
+func (p *PRPanel) updateFeedback(msg tea.KeyMsg, m *models.Model) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		if feedback := strings.TrimSpace(p.question.Value()); feedback != "" {
+			p.feedback = append(p.feedback, feedback)
+		}
+		p.question.Blur()
+		return p.Generate(m)
+	case "esc":
+		p.mode = prReview
+		p.question.Blur()
+		return p.focusField(p.focus)
+	}
+
+	var cmd tea.Cmd
+	p.question, cmd = p.question.Update(msg)
+	return cmd
+}
+
+func (p *PRPanel) focusField(field int) tea.Cmd {
+	p.blurFields()
+	p.focus = field
+	switch field {
+	case prFieldTitle:
+		return p.title.Focus()
+	case prFieldBody:
+		return p.body.Focus()
+	case prFieldReviewers:
+		return p.reviewers.Focus()
+	default:
+		return p.labels.Focus()
+	}
+}
+
+func (p *PRPanel) blurFields() {
+	p.title.Blur()
+	p.body.Blur()
+	p.reviewers.Blur()
+	p.labels.Blur()
+}
+
+// options collects the form
+func (p *PRPanel) options() github.PullRequestOptions {
+	return github.PullRequestOptions{
+		Title:     strings.TrimSpace(p.title.Value()),
+		Body:      strings.TrimSpace(p.body.Value()),
+		Base:      p.loaded.base,
+		Head:      p.input.Branch,
+		Draft:     p.draft,
+		Reviewers: splitList(p.reviewers.Value()),
+		Labels:    splitList(p.labels.Value()),
+	}
+}
+
+// submit pushes the branch, then creates the pull request or updates the
+// open one like update_existing_pr in auto_commit.zsh
+func (p *PRPanel) submit(m *models.Model) tea.Cmd {
+	opts := p.options()
+	if opts.Title == "" {
+		p.err = "The title cannot be empty"
+		return p.focusField(prFieldTitle)
+	}
+
+	existing := p.loaded.existing
+	if p.input.DryRun {
+		action := fmt.Sprintf("create a %s pull request into %s", p.draftName(), opts.Base)
+		if existing != nil {
+			action = fmt.Sprintf("push and update pull request #%d", existing.Number)
+		}
+		m.AddResult(fmt.Sprintf("🔍 Dry run: would %s: %s", action, opts.Title))
+		m.Panel = nil
+		return nil
+	}
+
+	p.mode, p.status, p.err = prSubmitting, "", ""
+	p.blurFields()
+	root := p.input.Root
+	return func() tea.Msg {
+		remote, _ := git.BranchRemote(root, opts.Head)
+		if remote == "" {
+			remote = "origin"
+		}
+		if err := git.Push(root, remote, opts.Head); err != nil {
+			return prSubmittedMsg{err: err}
+		}
+		if existing != nil {
+			return prSubmittedMsg{url: existing.URL, err: github.EditPullRequest(existing, opts)}
+		}
+		url, err := github.CreatePullRequest(opts)
+		return prSubmittedMsg{url: url, err: err}
+	}
+}
+
+func (p *PRPanel) draftName() string {
+	if p.draft {
+		return "draft"
+	}
+	return "ready"
+}
+
+// splitList reads comma or space separated names
+func splitList(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// resize fits the body editor between the rest of the form and the
+// single-line fields beside their labels
+func (p *PRPanel) resize(m *models.Model) {
+	for _, input := range []*textinput.Model{&p.title, &p.reviewers, &p.labels, &p.question} {
+		input.Width = max(20, m.Width-18)
+	}
+	p.body.SetWidth(max(20, m.Width-4))
+	p.body.SetHeight(max(3, listHeight(m.Height, 24)))
+}
+
+func (p *PRPanel) View(m models.Model) string {
+	var view string
+
+	title := "Pull request " + p.input.Branch
+	if p.loaded.base != "" {
+		title += " → " + p.loaded.base
+	}
+	details := ""
+	switch {
+	case p.loaded.existing != nil:
+		details = fmt.Sprintf("  updating #%d", p.loaded.existing.Number)
+	case p.mode != prLoading:
+		details = "  new"
+	}
+	if p.input.DryRun {
+		details += " • dry run"
+	}
+	view += ui.SuggestionStyle.Render(title+ui.BlurredStyle.Render(details)) + "\n\n"
+
+	if p.mode == prLoading {
+		view += ui.SuggestionStyle.Render("Collecting commits…") + "\n\n"
+		view += keyHints("esc cancel")
+		return view
+	}
+	view += p.viewCommits(m.Width)
+
+	switch p.mode {
+	case prGenerating:
+		view += ui.SuggestionStyle.Render("✦ Gemini is writing the pull request…") + "\n"
+		lines := strings.Split(strings.TrimSpace(p.reply), "\n")
+		for _, line := range lines[max(0, len(lines)-listHeight(m.Height, 20)):] {
+			view += "  " + ui.BlurredStyle.Render(truncate(line, max(20, m.Width-4))) + "\n"
+		}
+		view += "\n" + keyHints("esc cancel")
+		return view
+	case prFeedback:
+		view += ui.SuggestionStyle.Render("Feedback: ") + p.question.View() + "\n\n"
+		view += keyHints("enter regenerate", "esc back")
+		return view
+	}
+
+	view += p.label("Title:     ", prFieldTitle) + p.title.View() + "\n"
+	view += p.label("Body:", prFieldBody) + "\n" + p.body.View() + "\n"
+	view += p.label("Reviewers: ", prFieldReviewers) + p.reviewers.View() + "\n"
+	view += p.label("Labels:    ", prFieldLabels) + p.labels.View() + "\n"
+	draft, ready := ui.BlurredStyle.Render(" draft "), ui.SelectedSuggestionStyle.Padding(0).Render("‹ready›")
+	if p.draft {
+		draft, ready = ui.SelectedSuggestionStyle.Padding(0).Render("‹draft›"), ui.BlurredStyle.Render(" ready ")
+	}
+	view += ui.SuggestionStyle.Render("State:     ") + draft + " " + ready + "\n\n"
+
+	switch {
+	case p.mode == prSubmitting && p.loaded.existing != nil:
+		view += ui.SuggestionStyle.Render(fmt.Sprintf("Pushing and updating #%d…", p.loaded.existing.Number)) + "\n"
+	case p.mode == prSubmitting:
+		view += ui.SuggestionStyle.Render("Pushing and creating the pull request…") + "\n"
+	default:
+		if p.err != "" {
+			view += ui.WarningStyle.Render("❌ "+p.err) + "\n"
+		} else if p.status != "" {
+			view += ui.SuggestionStyle.Render("✓ "+p.status) + "\n"
+		}
+		submit := "ctrl+s push and create"
+		if p.loaded.existing != nil {
+			submit = "ctrl+s push and update"
+		}
+		view += keyHints("tab next field", "ctrl+d draft/ready", "ctrl+r regenerate with feedback", submit, "esc cancel")
+	}
+	return view
+}
+
+// viewCommits lists the first commits and the diffstat summary
+func (p *PRPanel) viewCommits(width int) string {
+	const shown = 4
+
+	var view string
+	for i, c := range p.loaded.commits {
+		if i == shown {
+			view += ui.BlurredStyle.Render(fmt.Sprintf("  …and %d more", len(p.loaded.commits)-shown)) + "\n"
+			break
+		}
+		view += "  " + ui.CommitHashStyle.Render(c.ShortHash) + " " + ui.MessageStyle.Render(truncate(c.Subject, max(20, width-14))) + "\n"
+	}
+	if lines := strings.Split(p.loaded.stat, "\n"); p.loaded.stat != "" {
+		view += "  " + ui.BlurredStyle.Render(strings.TrimSpace(lines[len(lines)-1])) + "\n"
+	}
+	return view + "\n"
+}
+
+// label renders a form label, highlighted while its field has focus
+func (p *PRPanel) label(text string, field int) string {
+	if p.focus == field {
+		return ui.SuggestionStyle.Foreground(ui.FocusedStyle.GetForeground()).Render(text)
+	}
+	return ui.SuggestionStyle.Render(text)
+}
@@ -0,0 +1,286 @@
+package panels
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+	"gemini-orchestrator/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusSection groups files the way git status does
+type statusSection int
+
+const (
+	sectionStaged statusSection = iota
+	sectionUnstaged
+	sectionUntracked
+)
+
+func (s statusSection) String() string {
+	switch s {
+	case sectionStaged:
+		return "Staged"
+	case sectionUnstaged:
+		return "Unstaged"
+	default:
+		return "Untracked"
+	}
+}
+
+// statusRow is a selectable file within a section; a file with both staged
+// and unstaged changes appears in both sections
+type statusRow struct {
+	section statusSection
+	file    git.File
+}
+
+func (r statusRow) key() string {
+	return fmt.Sprintf("%d:%s", r.section, r.file.Path)
+}
+
+// statusActionMsg reports a finished stage or unstage
+type statusActionMsg struct {
+	message string
+	err     error
+}
+
+// StatusPanel lists staged, unstaged and untracked files and stages or
+// unstages them. It renders m.Git, which the status bar keeps current.
+type StatusPanel struct {
+	selected    int
+	selectedKey string // Follows the selected file when the lists change
+	message     string
+	err         string
+}
+
+// NewStatusPanel opens the panel on the current repository status
+func NewStatusPanel() *StatusPanel {
+	return &StatusPanel{}
+}
+
+func statusRows(status *git.Status) []statusRow {
+	if status == nil {
+		return nil
+	}
+	var rows []statusRow
+	for _, f := range status.Staged() {
+		rows = append(rows, statusRow{sectionStaged, f})
+	}
+	for _, f := range status.Unstaged() {
+		rows = append(rows, statusRow{sectionUnstaged, f})
+	}
+	for _, f := range status.Untracked() {
+		rows = append(rows, statusRow{sectionUntracked, f})
+	}
+	return rows
+}
+
+// resolve finds the selected row after the status was refreshed
+func (p *StatusPanel) resolve(rows []statusRow) {
+	for i, row := range rows {
+		if row.key() == p.selectedKey {
+			p.selected = i
+			return
+		}
+	}
+	p.selected = max(0, min(p.selected, len(rows)-1))
+	if len(rows) > 0 {
+		p.selectedKey = rows[p.selected].key()
+	}
+}
+
+func (p *StatusPanel) move(rows []statusRow, index int) {
+	if index < 0 || index >= len(rows) {
+		return
+	}
+	p.selected = index
+	p.selectedKey = rows[index].key()
+}
+
+func (p *StatusPanel) Update(msg tea.Msg, m *models.Model) tea.Cmd {
+	rows := statusRows(m.Git)
+	p.resolve(rows)
+
+	switch msg := msg.(type) {
+	case statusActionMsg:
+		p.message, p.err = msg.message, ""
+		if msg.err != nil {
+			p.message, p.err = "", msg.err.Error()
+		}
+		return git.RefreshStatus()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			p.move(rows, p.selected-1)
+		case "down", "j":
+			p.move(rows, p.selected+1)
+		case "tab":
+			// Jump to the first file of the next section
+			for i := p.selected + 1; i < len(rows); i++ {
+				if rows[i].section != rows[p.selected].section {
+					p.move(rows, i)
+					break
+				}
+			}
+		case " ", "enter":
+			if len(rows) > 0 {
+				return p.toggle(m.Git.Root, rows[p.selected])
+			}
+		case "d":
+			if len(rows) > 0 {
+				return p.toggleDirectory(m.Git.Root, rows)
+			}
+		case "p":
+			if len(rows) > 0 {
+				return p.openHunks(rows[p.selected], m)
+			}
+		case "a":
+			return stageCmd(m.Git.Root, "Staged all changes", git.Stage, ".")
+		case "u":
+			return stageCmd(m.Git.Root, "Unstaged all changes", git.Unstage, ".")
+		case "r":
+			return git.RefreshStatus()
+		case "esc", "q":
+			m.Panel = nil
+		}
+	}
+	return nil
+}
+
+// openHunks stages or unstages parts of the selected file
+func (p *StatusPanel) openHunks(row statusRow, m *models.Model) tea.Cmd {
+	switch {
+	case row.section == sectionUntracked:
+		p.err = "Untracked files have no hunks yet, stage them with space"
+		return nil
+	case row.file.Kind == git.Unmerged:
+		p.err = "Resolve the conflict before staging parts of it"
+		return nil
+	}
+
+	hunks := NewHunkPanel(m.Git.Root, []string{row.file.Path}, row.section == sectionStaged, p)
+	m.Panel = hunks
+	return hunks.Load()
+}
+
+// toggle unstages a staged file, or stages an unstaged or untracked one
+func (p *StatusPanel) toggle(root string, row statusRow) tea.Cmd {
+	if row.section == sectionStaged {
+		paths := []string{row.file.Path}
+		if row.file.OrigPath != "" {
+			// Restoring only the new name would leave the deletion staged
+			paths = append(paths, row.file.OrigPath)
+		}
+		return stageCmd(root, "Unstaged "+row.file.Path, git.Unstage, paths...)
+	}
+	return stageCmd(root, "Staged "+row.file.Path, git.Stage, row.file.Path)
+}
+
+// toggleDirectory applies toggle to every file of the selected section in
+// the selected file's directory
+func (p *StatusPanel) toggleDirectory(root string, rows []statusRow) tea.Cmd {
+	selected := rows[p.selected]
+	dir := path.Dir(strings.TrimSuffix(selected.file.Path, "/"))
+
+	var paths []string
+	for _, row := range rows {
+		if row.section != selected.section {
+			continue
+		}
+		if dir != "." && !strings.HasPrefix(row.file.Path, dir+"/") {
+			continue
+		}
+		paths = append(paths, row.file.Path)
+		if row.section == sectionStaged && row.file.OrigPath != "" {
+			paths = append(paths, row.file.OrigPath)
+		}
+	}
+
+	label := dir + "/"
+	if dir == "." {
+		label = "the repository root"
+	}
+	if selected.section == sectionStaged {
+		return stageCmd(root, fmt.Sprintf("Unstaged %d file(s) in %s", len(paths), label), git.Unstage, paths...)
+	}
+	return stageCmd(root, fmt.Sprintf("Staged %d file(s) in %s", len(paths), label), git.Stage, paths...)
+}
+
+func stageCmd(root, message string, action func(string, ...string) error, paths ...string) tea.Cmd {
+	return func() tea.Msg {
+		return statusActionMsg{message: message, err: action(root, paths...)}
+	}
+}
+
+func (p *StatusPanel) View(m models.Model) string {
+	var view string
+
+	if m.Git == nil {
+		view += ui.WarningStyle.Render("Not a git repository") + "\n\n"
+		return view + keyHints("esc close")
+	}
+
+	branch := m.Git.Branch
+	if m.Git.Detached() {
+		branch = fmt.Sprintf("detached at %.7s", m.Git.Head)
+	}
+	view += ui.SuggestionStyle.Render("Repository status  ⎇ "+branch) + "\n\n"
+
+	rows := statusRows(m.Git)
+	p.resolve(rows)
+	if len(rows) == 0 {
+		view += ui.SuggestionStyle.Render("Nothing to commit, working tree clean") + "\n\n"
+	}
+
+	// The header, title, section headings, messages, hints and status bar
+	// take roughly fourteen lines
+	start, end := visibleRange(p.selected, len(rows), listHeight(m.Height, 14))
+	for i := start; i < end; i++ {
+		row := rows[i]
+		if i == start || rows[i-1].section != row.section {
+			count := 0
+			for _, r := range rows {
+				if r.section == row.section {
+					count++
+				}
+			}
+			view += ui.BlurredStyle.Render(fmt.Sprintf("  %s (%d)", row.section, count)) + "\n"
+		}
+
+		letter, letterStyle := row.file.Worktree, ui.UnstagedStyle
+		switch row.section {
+		case sectionStaged:
+			letter, letterStyle = row.file.Index, ui.StagedStyle
+		case sectionUntracked:
+			letterStyle = ui.BlurredStyle
+		}
+		name := row.file.Path
+		if row.file.OrigPath != "" && row.section == sectionStaged {
+			name = fmt.Sprintf("%s ← %s", row.file.Path, row.file.OrigPath)
+		}
+		if row.file.Kind == git.Unmerged {
+			name += " (conflict)"
+		}
+
+		style := ui.SuggestionStyle
+		if i == p.selected {
+			style = ui.SelectedSuggestionStyle
+		}
+		view += style.Render(letterStyle.Render(string(letter))+"  "+truncate(name, max(10, m.Width-10))) + "\n"
+	}
+	view += "\n"
+
+	if p.err != "" {
+		view += ui.WarningStyle.Render("❌ "+p.err) + "\n"
+	} else if p.message != "" {
+		view += ui.SuggestionStyle.Render("✓ "+p.message) + "\n"
+	}
+
+	view += keyHints("↑/↓ select", "space stage/unstage", "p hunks", "d directory", "a stage all", "u unstage all", "tab next section", "esc close")
+	return view
+}
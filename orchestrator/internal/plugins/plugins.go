@@ -0,0 +1,184 @@
+package plugins
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExecutablePrefix is the name prefix of plugin executables found on PATH
+const ExecutablePrefix = "gemini-orchestrator-"
+
+// Plugin sources, in order of precedence
+const (
+	SourceRepo = "repo" // <git root>/.gemini/commands/
+	SourceUser = "user" // ~/.config/gemini-cli/commands/
+	SourcePath = "path" // gemini-orchestrator-<name> on PATH
+)
+
+// How many lines at the top of a script are searched for metadata
+const headerLines = 20
+
+// Plugin is an external command exposed as a slash command
+type Plugin struct {
+	Name        string `json:"-"` // Command name without the leading "/"
+	Path        string `json:"-"`
+	Source      string `json:"-"`
+	Description string `json:"description"`
+	Args        string `json:"args"`
+	Interactive bool   `json:"interactive"` // Takes over the terminal like the auto-* scripts
+}
+
+// Command returns the slash command that runs the plugin
+func (p Plugin) Command() string {
+	return "/" + p.Name
+}
+
+// Discover finds plugins in the repository, user and PATH locations
+// When several sources provide the same name, the more specific one wins
+// and names in reserved (the built-in commands) are skipped entirely
+func Discover(reserved []string) []Plugin {
+	taken := map[string]bool{}
+	for _, name := range reserved {
+		taken[strings.TrimPrefix(name, "/")] = true
+	}
+
+	var found []Plugin
+	add := func(candidates []Plugin) {
+		for _, p := range candidates {
+			if taken[p.Name] {
+				continue
+			}
+			taken[p.Name] = true
+			found = append(found, p)
+		}
+	}
+
+	if dir := RepoDir(); dir != "" {
+		add(scanDir(dir, SourceRepo))
+	}
+	if dir := UserDir(); dir != "" {
+		add(scanDir(dir, SourceUser))
+	}
+	add(scanPath())
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found
+}
+
+// RepoDir returns the repository's shared command directory
+func RepoDir() string {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(strings.TrimSpace(string(output)), ".gemini", "commands")
+}
+
+// UserDir returns the user's personal command directory
+func UserDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gemini-cli", "commands")
+}
+
+func scanDir(dir, source string) []Plugin {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var found []Plugin
+	for _, entry := range entries {
+		fileName := entry.Name()
+		// Sidecar metadata and hidden files are not commands
+		if strings.HasPrefix(fileName, ".") || filepath.Ext(fileName) == ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, fileName)
+		if !isExecutable(path) {
+			continue
+		}
+
+		name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		found = append(found, load(name, path, source))
+	}
+	return found
+}
+
+func scanPath() []Plugin {
+	var found []Plugin
+	seen := map[string]bool{}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		matches, _ := filepath.Glob(filepath.Join(dir, ExecutablePrefix+"*"))
+		for _, path := range matches {
+			name := strings.TrimPrefix(filepath.Base(path), ExecutablePrefix)
+			// Earlier PATH entries shadow later ones, as in the shell
+			if name == "" || seen[name] || filepath.Ext(name) == ".json" || !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			found = append(found, load(name, path, SourcePath))
+		}
+	}
+	return found
+}
+
+// load reads metadata from a sidecar <name>.json file, falling back to
+// "# key: value" comments in the first lines of the script
+func load(name, path, source string) Plugin {
+	p := Plugin{Name: name, Path: path, Source: source, Interactive: true}
+
+	sidecar := strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+	if data, err := os.ReadFile(sidecar); err == nil {
+		if err := json.Unmarshal(data, &p); err == nil {
+			return p
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return p
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for i := 0; i < headerLines && scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "#")), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "description":
+			p.Description = value
+		case "args":
+			p.Args = value
+		case "interactive":
+			if interactive, err := strconv.ParseBool(value); err == nil {
+				p.Interactive = interactive
+			}
+		}
+	}
+	return p
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0
+}
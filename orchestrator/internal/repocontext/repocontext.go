@@ -0,0 +1,233 @@
+// Package repocontext loads the GEMINI.md files that describe a repository
+// to Gemini. Besides the file at the repository root, every directory on the
+// way to the working directory (and to any files being worked on) may have
+// its own GEMINI.md, as packages of a monorepo often do. Files are merged
+// from the root down, so more specific context comes last, and cut to a
+// token budget a section at a time.
+package repocontext
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileName is the context file looked for in each directory
+const FileName = "GEMINI.md"
+
+// DefaultBudget is the number of tokens context may use unless Options
+// says otherwise
+const DefaultBudget = 2000
+
+// Options selects the context files to load
+type Options struct {
+	Root     string           // Repository root; empty outside a repository
+	Dir      string           // Working directory
+	Paths    []string         // Root-relative files whose directories' GEMINI.md also apply
+	Budget   int              // Tokens; 0 uses DefaultBudget
+	Estimate func(string) int // Token count of a text; nil assumes 4 bytes per token
+}
+
+// Section is a part of a context file starting at a level 1 or 2 heading;
+// the text before the first heading is a section without a title
+type Section struct {
+	Title  string
+	Text   string
+	Tokens int
+	Kept   bool // Fits in the budget and is sent
+}
+
+// File is one GEMINI.md
+type File struct {
+	Path     string // Absolute path
+	Name     string // Path relative to the root, for display
+	Sections []Section
+}
+
+// Tokens returns the tokens of the kept sections and of the whole file
+func (f File) Tokens() (kept, total int) {
+	for _, s := range f.Sections {
+		total += s.Tokens
+		if s.Kept {
+			kept += s.Tokens
+		}
+	}
+	return kept, total
+}
+
+// Omitted returns the titles of the sections cut for the budget
+func (f File) Omitted() []string {
+	var titles []string
+	for _, s := range f.Sections {
+		if !s.Kept {
+			title := s.Title
+			if title == "" {
+				title = "introduction"
+			}
+			titles = append(titles, title)
+		}
+	}
+	return titles
+}
+
+// Context is the merged context, root first
+type Context struct {
+	Files  []File
+	Budget int
+	Tokens int // Tokens of the kept sections
+}
+
+// Load finds and reads the context files for opts. Sections are kept
+// starting with the most specific file, each file in document order; a
+// section that does not fit is left out but later, smaller ones may still
+// be kept.
+func Load(opts Options) (*Context, error) {
+	if opts.Budget <= 0 {
+		opts.Budget = DefaultBudget
+	}
+	if opts.Estimate == nil {
+		opts.Estimate = func(text string) int { return (len(text) + 3) / 4 }
+	}
+
+	c := &Context{Budget: opts.Budget}
+	for _, dir := range directories(opts) {
+		path := filepath.Join(dir, FileName)
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := FileName
+		if opts.Root != "" {
+			if rel, err := filepath.Rel(opts.Root, path); err == nil {
+				name = filepath.ToSlash(rel)
+			}
+		}
+		file := File{Path: path, Name: name, Sections: split(string(content))}
+		for i := range file.Sections {
+			file.Sections[i].Tokens = opts.Estimate(file.Sections[i].Text)
+		}
+		c.Files = append(c.Files, file)
+	}
+
+	for i := len(c.Files) - 1; i >= 0; i-- {
+		for j := range c.Files[i].Sections {
+			section := &c.Files[i].Sections[j]
+			if c.Tokens+section.Tokens <= c.Budget {
+				section.Kept = true
+				c.Tokens += section.Tokens
+			}
+		}
+	}
+	return c, nil
+}
+
+// Text is the context as sent to Gemini, empty when there is none
+func (c *Context) Text() string {
+	var parts []string
+	for _, f := range c.Files {
+		var sections []string
+		for _, s := range f.Sections {
+			if s.Kept {
+				sections = append(sections, s.Text)
+			}
+		}
+		if omitted := f.Omitted(); len(omitted) > 0 {
+			sections = append(sections, fmt.Sprintf("[Omitted to save tokens: %s]", strings.Join(omitted, ", ")))
+		}
+		if len(sections) == 0 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("--- %s ---\n%s", f.Name, strings.Join(sections, "\n\n")))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// Omitted counts the sections cut for the budget
+func (c *Context) Omitted() int {
+	count := 0
+	for _, f := range c.Files {
+		count += len(f.Omitted())
+	}
+	return count
+}
+
+// directories lists where to look for context files, the root first and
+// deeper directories after their parents
+func directories(opts Options) []string {
+	if opts.Root == "" {
+		if opts.Dir == "" {
+			return nil
+		}
+		return []string{opts.Dir}
+	}
+
+	seen := map[string]bool{}
+	var dirs []string
+	add := func(dir string) {
+		rel, err := filepath.Rel(opts.Root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return
+		}
+		// Add every directory from the root down to dir
+		current := opts.Root
+		for _, part := range append([]string{"."}, strings.Split(rel, string(filepath.Separator))...) {
+			current = filepath.Join(current, part)
+			if !seen[current] {
+				seen[current] = true
+				dirs = append(dirs, current)
+			}
+		}
+	}
+
+	add(opts.Root)
+	if opts.Dir != "" {
+		add(opts.Dir)
+	}
+	for _, path := range opts.Paths {
+		add(filepath.Join(opts.Root, filepath.Dir(path)))
+	}
+
+	slices.SortStableFunc(dirs, func(a, b string) int {
+		return strings.Count(a, string(filepath.Separator)) - strings.Count(b, string(filepath.Separator))
+	})
+	return dirs
+}
+
+// split cuts markdown into sections at level 1 and 2 headings outside
+// code fences
+func split(content string) []Section {
+	var sections []Section
+	var current []string
+	title := ""
+	fenced := false
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(current, "\n"))
+		if text != "" {
+			sections = append(sections, Section{Title: title, Text: text})
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		if !fenced && (strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ")) {
+			flush()
+			current = nil
+			title = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+		current = append(current, line)
+	}
+	flush()
+	return sections
+}
@@ -0,0 +1,286 @@
+package repocontext
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// monorepo creates a repository whose root and two packages have context
+// files; packages/ itself has none
+func monorepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// byteTokens counts a token per byte so budgets are easy to work out
+func byteTokens(text string) int {
+	return len(text)
+}
+
+func TestDirectories(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	join := func(parts ...string) string {
+		return filepath.Join(append([]string{root}, parts...)...)
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected []string
+	}{
+		{
+			name:     "outside a repository only the working directory",
+			opts:     Options{Dir: outside},
+			expected: []string{outside},
+		},
+		{
+			name: "nothing at all",
+			opts: Options{},
+		},
+		{
+			name:     "root down to the working directory",
+			opts:     Options{Root: root, Dir: join("packages", "api")},
+			expected: []string{root, join("packages"), join("packages", "api")},
+		},
+		{
+			name:     "a working directory outside the root is ignored",
+			opts:     Options{Root: root, Dir: outside},
+			expected: []string{root},
+		},
+		{
+			name: "directories of paths, parents before children",
+			opts: Options{Root: root, Dir: root, Paths: []string{"packages/web/src/app.ts", "packages/api/main.go", "README.md"}},
+			expected: []string{root, join("packages"), join("packages", "web"), join("packages", "api"),
+				join("packages", "web", "src")},
+		},
+		{
+			name:     "paths leaving the root are ignored and nothing is listed twice",
+			opts:     Options{Root: root, Dir: join("packages", "api"), Paths: []string{"../secrets/key.go", "packages/api/handler.go"}},
+			expected: []string{root, join("packages"), join("packages", "api")},
+		},
+	}
+
+	for _, test := range tests {
+		if actual := directories(test.opts); !reflect.DeepEqual(test.expected, actual) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		titles  []string
+		texts   []string
+	}{
+		{
+			name:    "empty",
+			content: "",
+		},
+		{
+			name:    "introduction and headings",
+			content: "Intro line\n\n# Title\nBody\n## Sub\nMore\n",
+			titles:  []string{"", "Title", "Sub"},
+			texts:   []string{"Intro line", "# Title\nBody", "## Sub\nMore"},
+		},
+		{
+			name:    "deeper headings and hashtags stay in their section",
+			content: "# Title\n### Detail\n#hashtag\n",
+			titles:  []string{"Title"},
+			texts:   []string{"# Title\n### Detail\n#hashtag"},
+		},
+		{
+			name:    "a blank introduction is dropped",
+			content: "\n   \n## Only\ntext",
+			titles:  []string{"Only"},
+			texts:   []string{"## Only\ntext"},
+		},
+		{
+			name:    "headings inside code fences do not split",
+			content: "# Shell\n```sh\n# a comment, not a heading\necho hi\n```\n~~~\n## neither is this\n~~~\n## Next\nafter",
+			titles:  []string{"Shell", "Next"},
+			texts: []string{"# Shell\n```sh\n# a comment, not a heading\necho hi\n```\n~~~\n## neither is this\n~~~",
+				"## Next\nafter"},
+		},
+		{
+			name:    "indented fences count too",
+			content: "# List\n- step\n  ```\n# inside\n  ```\n# After",
+			titles:  []string{"List", "After"},
+			texts:   []string{"# List\n- step\n  ```\n# inside\n  ```", "# After"},
+		},
+	}
+
+	for _, test := range tests {
+		var titles, texts []string
+		for _, section := range split(test.content) {
+			titles = append(titles, section.Title)
+			texts = append(texts, section.Text)
+		}
+		if !reflect.DeepEqual(test.titles, titles) {
+			t.Errorf("%s: expected titles %q, got %q", test.name, test.titles, titles)
+		}
+		if !reflect.DeepEqual(test.texts, texts) {
+			t.Errorf("%s: expected texts %q, got %q", test.name, test.texts, texts)
+		}
+	}
+}
+
+func TestLoadBudget(t *testing.T) {
+	const (
+		rootRules = "# Root\nshared rules"
+		rootStyle = "## Style\nuse tabs, keep lines short"
+		apiIntro  = "# API\nendpoints live in handlers/"
+		apiSmall  = "## Tests\nrun go test"
+	)
+	apiHuge := "## Reference\n" + strings.Repeat("x", 500)
+
+	root := monorepo(t, map[string]string{
+		"GEMINI.md":              rootRules + "\n\n" + rootStyle + "\n",
+		"packages/api/GEMINI.md": apiIntro + "\n\n" + apiHuge + "\n\n" + apiSmall + "\n",
+		"packages/web/GEMINI.md": "# Web\nnot on the way",
+	})
+	dir := filepath.Join(root, "packages", "api")
+
+	tests := []struct {
+		name    string
+		budget  int
+		kept    map[string][]bool // Kept flags by file name
+		omitted int
+		text    string
+	}{
+		{
+			name:   "everything fits",
+			budget: 10000,
+			kept: map[string][]bool{
+				"GEMINI.md":              {true, true},
+				"packages/api/GEMINI.md": {true, true, true},
+			},
+			text: "--- GEMINI.md ---\n" + rootRules + "\n\n" + rootStyle +
+				"\n\n--- packages/api/GEMINI.md ---\n" + apiIntro + "\n\n" + apiHuge + "\n\n" + apiSmall,
+		},
+		{
+			// The most specific file is filled first; the huge section is
+			// skipped but the smaller one after it still fits
+			name:   "later smaller sections are kept",
+			budget: len(apiIntro) + len(apiSmall) + len(rootRules),
+			kept: map[string][]bool{
+				"GEMINI.md":              {true, false},
+				"packages/api/GEMINI.md": {true, false, true},
+			},
+			omitted: 2,
+			text: "--- GEMINI.md ---\n" + rootRules + "\n\n[Omitted to save tokens: Style]" +
+				"\n\n--- packages/api/GEMINI.md ---\n" + apiIntro + "\n\n" + apiSmall + "\n\n[Omitted to save tokens: Reference]",
+		},
+		{
+			name:   "a file cut entirely still names what was omitted",
+			budget: len(apiIntro) + len(apiSmall),
+			kept: map[string][]bool{
+				"GEMINI.md":              {false, false},
+				"packages/api/GEMINI.md": {true, false, true},
+			},
+			omitted: 3,
+			text: "--- GEMINI.md ---\n[Omitted to save tokens: Root, Style]" +
+				"\n\n--- packages/api/GEMINI.md ---\n" + apiIntro + "\n\n" + apiSmall + "\n\n[Omitted to save tokens: Reference]",
+		},
+	}
+
+	for _, test := range tests {
+		c, err := Load(Options{Root: root, Dir: dir, Budget: test.budget, Estimate: byteTokens})
+		if err != nil {
+			t.Fatalf("%s: Load() failed: %v", test.name, err)
+		}
+
+		var names []string
+		for _, f := range c.Files {
+			names = append(names, f.Name)
+			var kept []bool
+			for _, s := range f.Sections {
+				kept = append(kept, s.Kept)
+			}
+			if !reflect.DeepEqual(test.kept[f.Name], kept) {
+				t.Errorf("%s: %s: expected kept %v, got %v", test.name, f.Name, test.kept[f.Name], kept)
+			}
+		}
+		if expected := []string{"GEMINI.md", "packages/api/GEMINI.md"}; !reflect.DeepEqual(expected, names) {
+			t.Errorf("%s: expected files %q, got %q", test.name, expected, names)
+		}
+		if c.Tokens > c.Budget {
+			t.Errorf("%s: %d tokens exceed the budget of %d", test.name, c.Tokens, c.Budget)
+		}
+		if c.Omitted() != test.omitted {
+			t.Errorf("%s: expected %d omitted sections, got %d", test.name, test.omitted, c.Omitted())
+		}
+		if c.Text() != test.text {
+			t.Errorf("%s: expected text\n%s\ngot\n%s", test.name, test.text, c.Text())
+		}
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	root := monorepo(t, map[string]string{
+		"GEMINI.md":              "Untitled introduction\n\n# Rules\n" + strings.Repeat("word ", 2000),
+		"packages/web/GEMINI.md": "# Web\nuse pnpm",
+	})
+
+	// The web package comes in through a path being worked on
+	c, err := Load(Options{Root: root, Dir: root, Paths: []string{"packages/web/src/app.ts"}})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if c.Budget != DefaultBudget {
+		t.Errorf("expected the default budget %d, got %d", DefaultBudget, c.Budget)
+	}
+	if len(c.Files) != 2 || c.Files[1].Name != "packages/web/GEMINI.md" {
+		t.Fatalf("expected the root and web files, got %+v", c.Files)
+	}
+	// 10000 bytes at 4 bytes a token do not fit in 2000 tokens
+	if omitted := c.Files[0].Omitted(); !reflect.DeepEqual([]string{"Rules"}, omitted) {
+		t.Errorf("expected Rules omitted, got %q", omitted)
+	}
+	if kept, total := c.Files[1].Tokens(); kept != total || kept != (len("# Web\nuse pnpm")+3)/4 {
+		t.Errorf("expected the web file kept at 4 bytes a token, got %d of %d", kept, total)
+	}
+
+	// Cutting the introduction names it
+	c, err = Load(Options{Root: root, Dir: root, Budget: 1, Estimate: byteTokens})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !strings.Contains(c.Text(), "[Omitted to save tokens: introduction, Rules]") {
+		t.Errorf("expected the introduction to be named, got %q", c.Text())
+	}
+}
+
+func TestLoadWithoutRepository(t *testing.T) {
+	dir := monorepo(t, map[string]string{"GEMINI.md": "plain notes"})
+	c, err := Load(Options{Dir: dir, Estimate: byteTokens})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if c.Text() != "--- GEMINI.md ---\nplain notes" {
+		t.Errorf("unexpected text %q", c.Text())
+	}
+
+	c, err = Load(Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if c.Text() != "" || len(c.Files) != 0 {
+		t.Errorf("expected no context, got %q", c.Text())
+	}
+}
@@ -0,0 +1,145 @@
+// Package sanitize turns raw model output into the text or structure a
+// feature asked for. It replaces the heuristics of gemini_clean.zsh and
+// gh_command_extraction.zsh on the Go side: CLI notices are removed wherever
+// they appear, a response wrapped in a markdown code fence is unwrapped, and
+// titles and bodies are parsed from JSON, TITLE:/BODY: blocks or gh commands
+// with errors that say what was wrong.
+package sanitize
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrEmpty is returned when nothing is left after cleaning
+var ErrEmpty = errors.New("the model returned an empty response")
+
+// noise matches whole lines the gemini CLI and Node.js print around the
+// answer, wherever they appear. Only exact notices are listed: the broader
+// first-line patterns of gemini_clean.zsh (Authentication.*, Cached.*) would
+// also remove answers such as a commit subject starting with "Cached".
+var noise = []*regexp.Regexp{
+	regexp.MustCompile(`^Loaded cached credentials\.?$`),
+	regexp.MustCompile(`^Data collection is disabled\.$`),
+	regexp.MustCompile(`^Flushing log events to Clearcut\.$`),
+	regexp.MustCompile(`^Attempt \d+ failed with status \d+\. Retrying with backoff\.\.\..*$`),
+	regexp.MustCompile(`^\(node:\d+\) (\[\w+\] )?\w*Warning: .*$`),
+	regexp.MustCompile("^\\(Use `node --trace-\\S+ \\.\\.\\.` to show where the warning was created\\)$"),
+	regexp.MustCompile(`^\[dotenv@[\d.]+\] .*$`),
+}
+
+// IsNoise reports whether line is CLI output rather than part of the answer
+func IsNoise(line string) bool {
+	line = strings.TrimSpace(line)
+	for _, pattern := range noise {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// noiseStarts are how the lines matched by noise begin
+var noiseStarts = []string{"Loaded cached credentials", "Data collection", "Flushing log events", "Attempt ", "(node:", "(Use `node", "[dotenv@"}
+
+// MayBeNoise reports whether a line of which only the start has arrived
+// could still turn out to be noise, so a stream holds it back until the
+// line is complete
+func MayBeNoise(start string) bool {
+	start = strings.TrimLeft(start, " \t")
+	for _, s := range noiseStarts {
+		if strings.HasPrefix(s, start) || strings.HasPrefix(start, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clean removes CLI noise lines and surrounding blank space
+func Clean(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if IsNoise(line) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// fence matches the opening or closing line of a markdown code block
+var fence = regexp.MustCompile("^\\s*(```+|~~~+)[\\w+.-]*\\s*$")
+
+// Unfence returns the content of a response that is a single code block,
+// optionally introduced by one line such as "Here is the message:" and
+// followed by one closing remark. Anything else is returned unchanged, so
+// a commit body that contains a code block stays intact.
+func Unfence(text string) string {
+	text = strings.TrimSpace(text)
+	lines := strings.Split(text, "\n")
+
+	var fences []int
+	for i, line := range lines {
+		if fence.MatchString(line) {
+			fences = append(fences, i)
+		}
+	}
+	if len(fences) != 2 {
+		return text
+	}
+	open, closing := fences[0], fences[1]
+	if open > 1 || len(lines)-1-closing > 1 {
+		return text
+	}
+	if open == 1 && !strings.HasSuffix(strings.TrimSpace(lines[0]), ":") {
+		return text
+	}
+	return strings.TrimSpace(strings.Join(lines[open+1:closing], "\n"))
+}
+
+// Text cleans and unfences a free-text response such as a commit message
+// or explanation
+func Text(output string) (string, error) {
+	text := Unfence(Clean(output))
+	if text == "" {
+		return "", ErrEmpty
+	}
+	return text, nil
+}
+
+// Line returns a one-line answer such as a branch name, without the
+// quotes or backticks models like to add
+func Line(output string) (string, error) {
+	text, err := Text(output)
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(text, "\n")
+	line = unquote(line)
+	if line == "" {
+		return "", ErrEmpty
+	}
+	return line, nil
+}
+
+// unquote removes one pair of quotes or backticks around text
+func unquote(text string) string {
+	text = strings.TrimSpace(text)
+	for _, quote := range []string{"`", `"`, "'"} {
+		if len(text) >= 2 && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			return strings.TrimSpace(text[1 : len(text)-1])
+		}
+	}
+	return text
+}
+
+// excerpt quotes the start of a response for error messages
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > 60 {
+		text = string(runes[:57]) + "..."
+	}
+	return `"` + text + `"`
+}
@@ -0,0 +1,101 @@
+package sanitize
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name, input, expected string
+	}{
+		{"plain", "feat: add login\n\nbody", "feat: add login\n\nbody"},
+		{"credentials first", "Loaded cached credentials.\nfeat: add login", "feat: add login"},
+		{"notices anywhere", "fix: x\nData collection is disabled.\n\nbody\nFlushing log events to Clearcut.", "fix: x\n\nbody"},
+		{"retry notice", "Attempt 1 failed with status 429. Retrying with backoff... GaxiosError\nanswer", "answer"},
+		{"node warning", "(node:1234) [DEP0040] DeprecationWarning: The `punycode` module is deprecated.\n(Use `node --trace-deprecation ...` to show where the warning was created)\nanswer", "answer"},
+		{"dotenv", "[dotenv@17.2.1] injecting env (0) from .env\nanswer", "answer"},
+		{"CRLF and blank space", "\r\n  answer\r\n\r\n", "answer"},
+		{"authentication subject kept", "Authentication: refresh tokens on 401", "Authentication: refresh tokens on 401"},
+		{"cached subject kept", "Cached results are now invalidated\n\nbody", "Cached results are now invalidated\n\nbody"},
+		{"loading subject kept", "Loading credentials lazily speeds up startup", "Loading credentials lazily speeds up startup"},
+		{"notice text inside a line kept", "Explain why Loaded cached credentials. appears", "Explain why Loaded cached credentials. appears"},
+		{"only noise", "Loaded cached credentials.\n", ""},
+	}
+	for _, test := range tests {
+		if got := Clean(test.input); got != test.expected {
+			t.Errorf("%s: Clean(%q) = %q, expected %q", test.name, test.input, got, test.expected)
+		}
+	}
+}
+
+func TestMayBeNoise(t *testing.T) {
+	tests := []struct {
+		start    string
+		expected bool
+	}{
+		{"Load", true},
+		{"Loaded cached credentials", true},
+		{"  (node:", true},
+		{"(nod", true},
+		{"Attempt 2 failed", true},
+		{"feat: ", false},
+		{"Cached", false},
+		{"Authentication", false},
+	}
+	for _, test := range tests {
+		if got := MayBeNoise(test.start); got != test.expected {
+			t.Errorf("MayBeNoise(%q) = %v, expected %v", test.start, got, test.expected)
+		}
+	}
+}
+
+func TestUnfence(t *testing.T) {
+	tests := []struct {
+		name, input, expected string
+	}{
+		{"no fence", "feat: x\n\nbody", "feat: x\n\nbody"},
+		{"fenced", "```\nfeat: x\n\nbody\n```", "feat: x\n\nbody"},
+		{"language tag", "```text\nfeat: x\n```", "feat: x"},
+		{"tildes", "~~~\nfeat: x\n~~~", "feat: x"},
+		{"intro line", "Here is the commit message:\n```\nfeat: x\n```", "feat: x"},
+		{"intro without colon kept", "Sure thing\n```\nfeat: x\n```", "Sure thing\n```\nfeat: x\n```"},
+		{"closing remark", "```\nfeat: x\n```\nLet me know if you want changes.", "feat: x"},
+		{"two remarks kept", "```\nfeat: x\n```\none\ntwo", "```\nfeat: x\n```\none\ntwo"},
+		{"code block in body kept", "feat: x\n\nRun:\n```\nmake\n```\nafterwards", "feat: x\n\nRun:\n```\nmake\n```\nafterwards"},
+		{"two blocks kept", "```\na\n```\n```\nb\n```", "```\na\n```\n```\nb\n```"},
+	}
+	for _, test := range tests {
+		if got := Unfence(test.input); got != test.expected {
+			t.Errorf("%s: Unfence(%q) = %q, expected %q", test.name, test.input, got, test.expected)
+		}
+	}
+}
+
+func TestTextAndLine(t *testing.T) {
+	if _, err := Text("Loaded cached credentials.\n\n"); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Text of noise only: expected ErrEmpty, got %v", err)
+	}
+	if got, err := Text("Loaded cached credentials.\n```\nfix: y\n```"); err != nil || got != "fix: y" {
+		t.Errorf("Text: got %q, %v", got, err)
+	}
+
+	tests := []struct {
+		input, expected string
+	}{
+		{"feat/add-login", "feat/add-login"},
+		{"`feat/add-login`", "feat/add-login"},
+		{`"feat/add-login"`, "feat/add-login"},
+		{"feat/add-login\nBecause the change adds login", "feat/add-login"},
+		{"```\nfix/timeout\n```", "fix/timeout"},
+		{`Say "hi"`, `Say "hi"`},
+	}
+	for _, test := range tests {
+		if got, err := Line(test.input); err != nil || got != test.expected {
+			t.Errorf("Line(%q) = %q, %v, expected %q", test.input, got, err, test.expected)
+		}
+	}
+	if _, err := Line(`""`); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Line of empty quotes: expected ErrEmpty, got %v", err)
+	}
+}
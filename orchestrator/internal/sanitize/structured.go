@@ -0,0 +1,220 @@
+package sanitize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TitleBody is a pull request or issue as written by the model
+type TitleBody struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// ParseTitleBody reads a title and body from a response in one of the forms
+// models use: a JSON object with "title" and "body", TITLE: and BODY: blocks,
+// or a gh command with --title and --body. The title is required and must be
+// one line; the body may be empty.
+func ParseTitleBody(output string) (TitleBody, error) {
+	text, err := Text(output)
+	if err != nil {
+		return TitleBody{}, err
+	}
+
+	var result TitleBody
+	switch {
+	case strings.HasPrefix(text, "{"):
+		if err := ParseJSON(text, &result); err != nil {
+			return TitleBody{}, err
+		}
+	case blockLabel.MatchString(text):
+		result, err = parseBlocks(text)
+		if err != nil {
+			return TitleBody{}, err
+		}
+	case ghCommand.MatchString(text):
+		result, err = parseCommand(text[ghCommand.FindStringIndex(text)[0]:])
+		if err != nil {
+			return TitleBody{}, err
+		}
+	case strings.Contains(text, "{"):
+		if err := ParseJSON(text, &result); err != nil {
+			return TitleBody{}, err
+		}
+	default:
+		return TitleBody{}, fmt.Errorf("expected a title and body as JSON, TITLE:/BODY: blocks or a gh command, got %s", excerpt(text))
+	}
+
+	result.Title = unquote(result.Title)
+	result.Body = strings.TrimSpace(result.Body)
+	if result.Title == "" {
+		return TitleBody{}, fmt.Errorf("the response has no title: %s", excerpt(text))
+	}
+	if strings.Contains(result.Title, "\n") {
+		return TitleBody{}, fmt.Errorf("the title spans several lines: %s", excerpt(result.Title))
+	}
+	return result, nil
+}
+
+// ParseJSON decodes the JSON object or array in a response into v. Text
+// around the JSON, such as a code fence or a sentence of introduction, is
+// ignored.
+func ParseJSON(output string, v any) error {
+	text := Unfence(Clean(output))
+	if text == "" {
+		return ErrEmpty
+	}
+
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return fmt.Errorf("expected JSON, got %s", excerpt(text))
+	}
+	closing := "}"
+	if text[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(text, closing)
+	if end < start {
+		return fmt.Errorf("the JSON in the response is incomplete: %s", excerpt(text[start:]))
+	}
+
+	data := text[start : end+1]
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			return fmt.Errorf("the response is not valid JSON (%v) near %s", err, excerpt(data[max(0, int(syntax.Offset)-20):]))
+		}
+		return fmt.Errorf("the JSON in the response has the wrong shape: %w", err)
+	}
+	return nil
+}
+
+// blockLabel matches a TITLE: or BODY: line, also in markdown bold
+var blockLabel = regexp.MustCompile(`(?im)^\s*(\*\*)?(title|body)(\*\*)?\s*:(\*\*)?[ \t]*`)
+
+// parseBlocks reads "TITLE: ..." followed by "BODY:" and the body on the
+// following lines. Everything after BODY: belongs to the body, even lines
+// that look like labels.
+func parseBlocks(text string) (TitleBody, error) {
+	var result TitleBody
+	var seen = map[string]bool{}
+
+	matches := blockLabel.FindAllStringSubmatchIndex(text, -1)
+	for i, match := range matches {
+		label := strings.ToLower(text[match[4]:match[5]])
+		end := len(text)
+		if i+1 < len(matches) && label == "title" {
+			end = matches[i+1][0]
+		}
+		value := strings.TrimSpace(text[match[1]:end])
+
+		if seen[label] {
+			return TitleBody{}, fmt.Errorf("the response has more than one %s", strings.ToUpper(label))
+		}
+		seen[label] = true
+		if label == "title" {
+			result.Title = value
+		} else {
+			result.Body = value
+			break
+		}
+	}
+	if !seen["title"] {
+		return TitleBody{}, fmt.Errorf("the response has a BODY but no TITLE: %s", excerpt(text))
+	}
+	return result, nil
+}
+
+// ghCommand matches the start of a gh command line
+var ghCommand = regexp.MustCompile(`(?m)^\s*gh (pr|issue) `)
+
+// parseCommand reads the --title and --body arguments of a gh command
+func parseCommand(text string) (TitleBody, error) {
+	words, err := shellWords(text)
+	if err != nil {
+		return TitleBody{}, fmt.Errorf("could not read the gh command: %w", err)
+	}
+
+	var result TitleBody
+	for i := 0; i < len(words); i++ {
+		flag, value, inline := strings.Cut(words[i], "=")
+		var target *string
+		switch flag {
+		case "--title", "-t":
+			target = &result.Title
+		case "--body", "-b":
+			target = &result.Body
+		default:
+			continue
+		}
+		if !inline {
+			if i+1 >= len(words) {
+				return TitleBody{}, fmt.Errorf("%s in the gh command has no value", flag)
+			}
+			i++
+			value = words[i]
+		}
+		*target = value
+	}
+	return result, nil
+}
+
+// shellWords splits a command line the way a POSIX shell would for single
+// quotes, double quotes and backslashes. Line continuations are joined.
+func shellWords(text string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+			if r == '\n' {
+				continue
+			}
+			inWord = true
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,112 @@
+package sanitize
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTitleBody(t *testing.T) {
+	tests := []struct {
+		name, input string
+		expected    TitleBody
+	}{
+		{"json", `{"title": "Fix login", "body": "## Summary\n- fixed"}`, TitleBody{"Fix login", "## Summary\n- fixed"}},
+		{"fenced json with notice", "Loaded cached credentials.\n```json\n{\"title\": \"Fix login\", \"body\": \"\"}\n```", TitleBody{"Fix login", ""}},
+		{"json after intro", "Here you go:\n{\"title\": \"Fix login\", \"body\": \"b\"}", TitleBody{"Fix login", "b"}},
+		{"blocks", "TITLE: Fix login\nBODY: ## Summary\n- fixed", TitleBody{"Fix login", "## Summary\n- fixed"}},
+		{"lowercase bold blocks", "**Title:** Fix login\n**Body:**\nline one\nline two", TitleBody{"Fix login", "line one\nline two"}},
+		{"label inside body", "TITLE: Fix login\nBODY: Notes\nTitle: stays in the body", TitleBody{"Fix login", "Notes\nTitle: stays in the body"}},
+		{"quoted title", "TITLE: \"Fix login\"\nBODY: b", TitleBody{"Fix login", "b"}},
+		{"title ending in a quote", `TITLE: Handle the "remember me" flag` + "\nBODY: b", TitleBody{`Handle the "remember me" flag`, "b"}},
+		{"title only", "TITLE: Fix login", TitleBody{"Fix login", ""}},
+		{"gh command", `gh pr create --title "Fix login" --body "## Summary
+- it's \"fixed\""`, TitleBody{"Fix login", "## Summary\n- it's \"fixed\""}},
+		{"gh short flags", `gh issue create -t 'Crash on start' -b 'Steps: run it' --label bug`, TitleBody{"Crash on start", "Steps: run it"}},
+		{"gh equals form", `gh pr create --title="Fix login" --body=done`, TitleBody{"Fix login", "done"}},
+		{"gh continuation", "gh pr create \\\n  --title \"Fix login\" \\\n  --body \"b\"", TitleBody{"Fix login", "b"}},
+		{"gh after intro", "Run this:\ngh pr create --title 'Fix login' --body 'b'", TitleBody{"Fix login", "b"}},
+	}
+	for _, test := range tests {
+		got, err := ParseTitleBody(test.input)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if got != test.expected {
+			t.Errorf("%s: got %+v, expected %+v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestParseTitleBodyErrors(t *testing.T) {
+	tests := []struct {
+		name, input, expected string
+	}{
+		{"empty", "Loaded cached credentials.", "empty response"},
+		{"prose", "I could not find any commits.", "expected a title and body"},
+		{"no title", `{"body": "b"}`, "has no title"},
+		{"multi-line title", `{"title": "one\ntwo"}`, "spans several lines"},
+		{"body without title", "BODY: b", "BODY but no TITLE"},
+		{"two titles", "TITLE: a\nTITLE: b\nBODY: c", "more than one TITLE"},
+		{"invalid json", `{"title": "a", "body": }`, "not valid JSON"},
+		{"wrong shape", `{"title": ["a"]}`, "wrong shape"},
+		{"unterminated quote", `gh pr create --title "Fix login --body b`, "unterminated"},
+		{"flag without value", `gh pr create --body b --title`, "has no value"},
+	}
+	for _, test := range tests {
+		_, err := ParseTitleBody(test.input)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected an error containing %q, got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	var object struct {
+		Operation string `json:"operation"`
+		Number    int    `json:"number"`
+	}
+	if err := ParseJSON("Sure:\n```json\n{\"operation\": \"close\", \"number\": 12}\n```", &object); err != nil || object.Operation != "close" || object.Number != 12 {
+		t.Errorf("object: got %+v, %v", object, err)
+	}
+
+	var list []string
+	if err := ParseJSON(`The labels are ["bug", "good first issue"].`, &list); err != nil || !slices.Equal(list, []string{"bug", "good first issue"}) {
+		t.Errorf("array: got %q, %v", list, err)
+	}
+
+	errors := []struct {
+		input, expected string
+	}{
+		{"", "empty response"},
+		{"no json here", "expected JSON"},
+		{`{"operation": "close"`, "incomplete"},
+		{`{"number": "twelve"}`, "wrong shape"},
+		{`{"operation": close}`, "not valid JSON"},
+	}
+	for _, test := range errors {
+		err := ParseJSON(test.input, &object)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("ParseJSON(%q): expected an error containing %q, got %v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestShellWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`a b  c`, []string{"a", "b", "c"}},
+		{`'it''s' "a b"`, []string{"its", "a b"}},
+		{`"say \"hi\"" 'no \escape'`, []string{`say "hi"`, `no \escape`}},
+		{`"keep \n" a\ b`, []string{`keep \n`, "a b"}},
+		{`"" x`, []string{"", "x"}},
+		{"a \\\n b", []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		got, err := shellWords(test.input)
+		if err != nil || !slices.Equal(got, test.expected) {
+			t.Errorf("shellWords(%q) = %q, %v, expected %q", test.input, got, err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package script
+
+// Run tracks the progress of a script being executed by the orchestrator
+type Run struct {
+	Script  *Script
+	Current int // Index of the step currently executing, -1 before the first step
+	Failed  int
+	Waiting bool // True while an asynchronous step (e.g. tea.ExecProcess) is in flight
+}
+
+func NewRun(s *Script) *Run {
+	return &Run{Script: s, Current: -1}
+}
+
+// Next advances to the following step, returning false when the script is finished
+func (r *Run) Next() (Step, bool) {
+	r.Current++
+	if r.Current >= len(r.Script.Steps) {
+		return Step{}, false
+	}
+	return r.Script.Steps[r.Current], true
+}
+
+// Step returns the step currently executing
+func (r *Run) Step() Step {
+	return r.Script.Steps[r.Current]
+}
+
+// Progress returns a short "3/5" style position indicator
+func (r *Run) Progress() (int, int) {
+	return r.Current + 1, len(r.Script.Steps)
+}
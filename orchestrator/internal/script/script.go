@@ -0,0 +1,184 @@
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrorPolicy decides what happens when a step fails
+type ErrorPolicy string
+
+const (
+	OnErrorStop     ErrorPolicy = "stop"
+	OnErrorContinue ErrorPolicy = "continue"
+	OnErrorAsk      ErrorPolicy = "ask"
+)
+
+// StepKind identifies what a script line does when executed
+type StepKind int
+
+const (
+	StepCommand StepKind = iota // Slash command or plain input, e.g. "/commit fix bug"
+	StepZsh                     // Zsh mode command, e.g. "! git add -A"
+	StepConfirm                 // Pause and ask the user before continuing
+)
+
+// Step is a single executable line of a script
+type Step struct {
+	Kind    StepKind
+	Text    string // Command text with variables expanded (prompt text for confirm steps)
+	Line    int
+	OnError ErrorPolicy
+}
+
+// Script is a parsed batch file of orchestrator commands
+type Script struct {
+	Name  string
+	Steps []Step
+}
+
+var (
+	variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	namePattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Load reads and parses a script file
+// Variables in overrides take precedence over "set" lines in the file
+func Load(path string, overrides map[string]string) (*Script, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(filepath.Base(path), file, overrides)
+}
+
+// Parse reads a script from r. The format is line based:
+//
+//	# comment
+//	set BASE=main            define a variable, used as ${BASE}
+//	on-error continue        stop (default), continue or ask for the following steps
+//	confirm Push to remote?  ask before running the next step
+//	/commit fix ${SCOPE}     any orchestrator slash command
+//	! git push               any zsh mode command
+func Parse(name string, r io.Reader, overrides map[string]string) (*Script, error) {
+	vars := make(map[string]string, len(overrides))
+	for key, value := range overrides {
+		vars[key] = value
+	}
+
+	s := &Script{Name: name}
+	policy := OnErrorStop
+	lineNumber := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		expanded, err := expand(line, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, lineNumber, err)
+		}
+
+		switch {
+		case strings.HasPrefix(expanded, "/"):
+			s.Steps = append(s.Steps, Step{Kind: StepCommand, Text: expanded, Line: lineNumber, OnError: policy})
+		case strings.HasPrefix(expanded, "!"):
+			command := strings.TrimSpace(strings.TrimPrefix(expanded, "!"))
+			if command == "" {
+				return nil, fmt.Errorf("%s:%d: empty zsh command", name, lineNumber)
+			}
+			s.Steps = append(s.Steps, Step{Kind: StepZsh, Text: command, Line: lineNumber, OnError: policy})
+		default:
+			directive, argument, _ := strings.Cut(expanded, " ")
+			argument = strings.TrimSpace(argument)
+
+			switch directive {
+			case "set":
+				key, value, found := strings.Cut(argument, "=")
+				key = strings.TrimSpace(key)
+				if !found || !namePattern.MatchString(key) {
+					return nil, fmt.Errorf("%s:%d: expected set NAME=value", name, lineNumber)
+				}
+				// Values passed by the caller win over the script's own defaults
+				if _, overridden := overrides[key]; !overridden {
+					vars[key] = strings.TrimSpace(value)
+				}
+			case "on-error":
+				switch ErrorPolicy(argument) {
+				case OnErrorStop, OnErrorContinue, OnErrorAsk:
+					policy = ErrorPolicy(argument)
+				default:
+					return nil, fmt.Errorf("%s:%d: on-error must be stop, continue or ask", name, lineNumber)
+				}
+			case "confirm":
+				prompt := argument
+				if prompt == "" {
+					prompt = "Continue?"
+				}
+				s.Steps = append(s.Steps, Step{Kind: StepConfirm, Text: prompt, Line: lineNumber, OnError: policy})
+			default:
+				return nil, fmt.Errorf("%s:%d: unknown directive %q (commands start with / or !)", name, lineNumber, directive)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(s.Steps) == 0 {
+		return nil, fmt.Errorf("%s: no commands to run", name)
+	}
+	return s, nil
+}
+
+// ParseArgs splits "/run" arguments into the script path and KEY=VALUE variables
+func ParseArgs(args []string) (string, map[string]string, error) {
+	var path string
+	vars := map[string]string{}
+
+	for _, arg := range args {
+		if key, value, found := strings.Cut(arg, "="); found && namePattern.MatchString(key) {
+			vars[key] = value
+			continue
+		}
+		if path != "" {
+			return "", nil, fmt.Errorf("unexpected argument %q", arg)
+		}
+		path = arg
+	}
+
+	if path == "" {
+		return "", nil, fmt.Errorf("usage: /run <file> [NAME=value ...]")
+	}
+	return path, vars, nil
+}
+
+func expand(line string, vars map[string]string) (string, error) {
+	var missing string
+	expanded := variablePattern.ReplaceAllStringFunc(line, func(match string) string {
+		key := variablePattern.FindStringSubmatch(match)[1]
+		value, ok := vars[key]
+		if !ok && missing == "" {
+			missing = key
+		}
+		return value
+	})
+
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable ${%s}", missing)
+	}
+	return expanded, nil
+}
@@ -0,0 +1,161 @@
+package script
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, source string, overrides map[string]string) *Script {
+	t.Helper()
+	s, err := Parse("test.run", strings.NewReader(source), overrides)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	return s
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		overrides map[string]string
+		expected  []Step
+	}{
+		{
+			name:   "comments and blank lines are skipped but counted",
+			source: "# setup\n\n   \n\t# indented comment\n/status\n",
+			expected: []Step{
+				{Kind: StepCommand, Text: "/status", Line: 5, OnError: OnErrorStop},
+			},
+		},
+		{
+			name:   "surrounding whitespace is trimmed",
+			source: "   /diff --staged   \n",
+			expected: []Step{
+				{Kind: StepCommand, Text: "/diff --staged", Line: 1, OnError: OnErrorStop},
+			},
+		},
+		{
+			name:   "a hash inside a command is not a comment",
+			source: "/start #12 --assign\n! echo a # b\n",
+			expected: []Step{
+				{Kind: StepCommand, Text: "/start #12 --assign", Line: 1, OnError: OnErrorStop},
+				{Kind: StepZsh, Text: "echo a # b", Line: 2, OnError: OnErrorStop},
+			},
+		},
+		{
+			name:   "quotes are kept for the command to interpret",
+			source: "/commit \"fix: quoted scope\"\n! git commit -m 'it''s done' \"$HOME\"\n",
+			expected: []Step{
+				{Kind: StepCommand, Text: `/commit "fix: quoted scope"`, Line: 1, OnError: OnErrorStop},
+				{Kind: StepZsh, Text: `git commit -m 'it''s done' "$HOME"`, Line: 2, OnError: OnErrorStop},
+			},
+		},
+		{
+			name:   "variables are expanded inside quotes",
+			source: "set SCOPE=ui layer\n/commit \"fix ${SCOPE}\"\n",
+			expected: []Step{
+				{Kind: StepCommand, Text: `/commit "fix ui layer"`, Line: 2, OnError: OnErrorStop},
+			},
+		},
+		{
+			name:      "overrides win over set lines",
+			source:    "set BASE=main\n! git rebase ${BASE}\n",
+			overrides: map[string]string{"BASE": "develop"},
+			expected: []Step{
+				{Kind: StepZsh, Text: "git rebase develop", Line: 2, OnError: OnErrorStop},
+			},
+		},
+		{
+			name:   "on-error applies to the following steps",
+			source: "/status\non-error continue\n/diff\non-error ask\nconfirm\nconfirm Push now?\n",
+			expected: []Step{
+				{Kind: StepCommand, Text: "/status", Line: 1, OnError: OnErrorStop},
+				{Kind: StepCommand, Text: "/diff", Line: 3, OnError: OnErrorContinue},
+				{Kind: StepConfirm, Text: "Continue?", Line: 5, OnError: OnErrorAsk},
+				{Kind: StepConfirm, Text: "Push now?", Line: 6, OnError: OnErrorAsk},
+			},
+		},
+		{
+			name:   "a final line without a newline is read",
+			source: "/status\n! git push",
+			expected: []Step{
+				{Kind: StepCommand, Text: "/status", Line: 1, OnError: OnErrorStop},
+				{Kind: StepZsh, Text: "git push", Line: 2, OnError: OnErrorStop},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s := parse(t, test.source, test.overrides)
+		if !reflect.DeepEqual(test.expected, s.Steps) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, s.Steps)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{"only comments", "# nothing\n\n", "test.run: no commands to run"},
+		{"undefined variable", "/commit ${SCOPE}\n", "test.run:1: undefined variable ${SCOPE}"},
+		{"empty zsh command", "!   \n", "test.run:1: empty zsh command"},
+		{"unknown directive", "/status\ncommit fix\n", `test.run:2: unknown directive "commit" (commands start with / or !)`},
+		{"bad set", "set 1X=a\n", "test.run:1: expected set NAME=value"},
+		{"set without value", "set NAME\n", "test.run:1: expected set NAME=value"},
+		{"bad policy", "on-error retry\n", "test.run:1: on-error must be stop, continue or ask"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse("test.run", strings.NewReader(test.source), nil)
+		if err == nil {
+			t.Errorf("%s: expected error %q, got none", test.name, test.err)
+			continue
+		}
+		if err.Error() != test.err {
+			t.Errorf("%s: expected error %q, got %q", test.name, test.err, err.Error())
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	path, vars, err := ParseArgs([]string{"release.run", "BASE=main", "MESSAGE=a=b"})
+	if err != nil {
+		t.Fatalf("ParseArgs() failed: %v", err)
+	}
+	if path != "release.run" {
+		t.Errorf("expected path release.run, got %q", path)
+	}
+	if expected := map[string]string{"BASE": "main", "MESSAGE": "a=b"}; !reflect.DeepEqual(expected, vars) {
+		t.Errorf("expected variables %v, got %v", expected, vars)
+	}
+
+	for _, args := range [][]string{nil, {"BASE=main"}, {"a.run", "b.run"}} {
+		if _, _, err := ParseArgs(args); err == nil {
+			t.Errorf("ParseArgs(%q) should fail", args)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	r := NewRun(parse(t, "/status\n/diff\n", nil))
+	for _, expected := range []string{"/status", "/diff"} {
+		step, ok := r.Next()
+		if !ok || step.Text != expected {
+			t.Fatalf("expected step %q, got %q (ok %v)", expected, step.Text, ok)
+		}
+		if r.Step() != step {
+			t.Errorf("Step() should return the step Next() returned")
+		}
+	}
+	if current, total := r.Progress(); current != 2 || total != 2 {
+		t.Errorf("expected progress 2/2, got %d/%d", current, total)
+	}
+	if _, ok := r.Next(); ok {
+		t.Errorf("Next() should report the end of the script")
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"gemini-orchestrator/internal/models"
 
@@ -17,7 +18,13 @@ func RenderContent(m models.Model) string {
 
 	// Messages history
 	if len(m.Messages) > 0 {
-		for _, msg := range m.Messages {
+		for i, msg := range m.Messages {
+			// The chat answer being streamed belongs to the latest message
+			if i == len(m.Messages)-1 && m.ChatStream != nil {
+				if reply := strings.TrimSpace(m.ChatReply); reply != "" {
+					msg += "\n  ⎿  " + models.IndentResponse(reply)
+				}
+			}
 			content += MessageStyle.Render(fmt.Sprintf("> %s", msg)) + "\n"
 		}
 		content += "\n"
@@ -53,6 +60,16 @@ func RenderInputBar(m models.Model) string {
 func RenderView(m models.Model) string {
 	var view string
 
+	// Full-screen panels replace the conversation until they close
+	if m.Panel != nil {
+		view += RenderHeader()
+		view += m.Panel.View(m)
+		if m.ShowExitConfirm {
+			view += HelpTextStyle.Render("Press Ctrl+C again to exit (or Esc to cancel)")
+		}
+		return view + "\n" + RenderStatusBar(m) + "\n"
+	}
+
 	// Composable UI layout
 	view += RenderHeader()
 	view += RenderContent(m)
@@ -62,6 +79,26 @@ func RenderView(m models.Model) string {
 		view += SuggestionStyle.Render(fmt.Sprintf("%s Building and reloading...", m.Spinner.View())) + "\n\n"
 	}
 
+	// Show that Gemini is answering a chat message
+	if m.ChatStream != nil {
+		status := "✦ Gemini is answering..."
+		if m.ChatReply == "" {
+			status = "✦ Gemini is thinking..."
+		}
+		view += SuggestionStyle.Render(status) + BlurredStyle.Render(" (Esc to cancel)") + "\n\n"
+	}
+
+	// Show batch script progress
+	if m.Script != nil {
+		step, total := m.Script.Progress()
+		view += SuggestionStyle.Render(fmt.Sprintf("▶ Running %s (step %d/%d)", m.Script.Script.Name, step, total)) + "\n\n"
+	}
+
+	// Show files attached to the session context
+	if len(m.ContextFiles) > 0 {
+		view += BlurredStyle.Render("  📎 "+strings.Join(m.ContextFiles, ", ")) + "\n"
+	}
+
 	view += RenderInputBar(m)
 
 	// Only show UI elements if not building
@@ -69,33 +106,46 @@ func RenderView(m models.Model) string {
 		if m.ShowExitConfirm {
 			// Priority 1: Exit confirmation (overrides everything else)
 			view += HelpTextStyle.Render("Press Ctrl+C again to exit (or Esc to cancel)")
+		} else if m.Confirm != nil {
+			// Priority 2: Pending yes/no question
+			view += ConfirmStyle.Render(m.Confirm.Prompt + " (y/n)")
 		} else if m.ShowSuggestions && len(m.Suggestions) > 0 {
-			// Priority 2: Suggestions dropdown
+			// Priority 3: Suggestions dropdown
 			view += "\n"
+			nameWidth := 0
+			for _, suggestion := range m.Suggestions {
+				nameWidth = max(nameWidth, len(suggestion))
+			}
 			for i, suggestion := range m.Suggestions {
+				name := fmt.Sprintf("%-*s", nameWidth, suggestion)
 				if i == m.SelectedSuggestion {
-					view += SelectedSuggestionStyle.Render(suggestion) + "\n"
+					view += SelectedSuggestionStyle.Render(name)
 				} else {
-					view += SuggestionStyle.Render(suggestion) + "\n"
+					view += SuggestionStyle.Render(name)
+				}
+				if description := m.CommandDescription(suggestion); description != "" {
+					view += BlurredStyle.Render(description)
 				}
+				view += "\n"
 			}
 			view += "\n"
 			view += BlurredStyle.Render("↑/↓ to navigate • Tab to complete • Enter to execute")
 		} else if m.ShowHelp {
-			// Priority 3: Help shortcuts
+			// Priority 4: Help shortcuts
 			view += "\n"
 			formattedShortcuts := DistributeShortcuts(m.Width)
 			for _, shortcut := range formattedShortcuts {
 				view += SuggestionStyle.Render(shortcut) + "\n"
 			}
 		} else if !m.ZshMode {
-			// Priority 4: Default help prompt (only when not in zsh mode)
+			// Priority 5: Default help prompt (only when not in zsh mode)
 			view += HelpTextStyle.Render("? for shortcuts")
 		}
 	}
 
 	view += "\n"
 	view += "\n"
+	view += RenderStatusBar(m) + "\n"
 
 	return view
 }
@@ -108,3 +158,9 @@ func ComposeUI(m *models.Model) {
 	// Reset UI state - Bubble Tea will handle the visual refresh automatically
 	// This is the recommended approach rather than direct console manipulation
 }
+
+// Hyperlink makes text a link to url with an OSC 8 escape sequence;
+// terminals without support show the text alone
+func Hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
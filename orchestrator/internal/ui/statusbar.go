@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const statusSeparator = " │ "
+
+// statusSegment is one part of the status bar; when the terminal is too
+// narrow the segments with the highest priority number are dropped first
+type statusSegment struct {
+	text     string
+	style    lipgloss.Style
+	priority int
+}
+
+// RenderStatusBar shows where the session is: repository, branch, working
+// tree state, upstream, pull request, model and dry-run
+func RenderStatusBar(m models.Model) string {
+	var segments []statusSegment
+	add := func(text string, style lipgloss.Style, priority int) {
+		segments = append(segments, statusSegment{text, style, priority})
+	}
+
+	if m.Git != nil {
+		branch := m.Git.Branch
+		if m.Git.Detached() {
+			branch = fmt.Sprintf("(detached %.7s)", m.Git.Head)
+		}
+		add(git.RepoName(m.Git.Root), BlurredStyle, 6)
+		add("⎇ "+branch, StatusBarBranchStyle, 0)
+
+		staged, unstaged, untracked := m.Git.Counts()
+		if m.Git.Clean() {
+			add("✓ clean", BlurredStyle, 1)
+		} else {
+			add(fmt.Sprintf("●%d ✚%d …%d", staged, unstaged, untracked), StatusBarDirtyStyle, 1)
+		}
+
+		if m.Git.HasUpstream {
+			add(fmt.Sprintf("↑%d ↓%d", m.Git.Ahead, m.Git.Behind), BlurredStyle, 3)
+		} else if !m.Git.Detached() {
+			add("no upstream", BlurredStyle, 3)
+		}
+
+		if m.PullRequest != nil && m.PullRequestBranch == m.Git.Branch {
+			add(fmt.Sprintf("PR #%d", m.PullRequest.Number), BlurredStyle, 5)
+		}
+	} else {
+		add("not a git repository", BlurredStyle, 0)
+	}
+
+	model, _ := m.Config.Model(m.Overrides)
+	add("✦ "+model, BlurredStyle, 4)
+	if m.Overrides.DryRun {
+		add("DRY-RUN", WarningStyle.Padding(0), 2)
+	}
+
+	return layoutStatusBar(segments, m.Width)
+}
+
+// layoutStatusBar drops low-priority segments until the bar fits in width,
+// then truncates what is left
+func layoutStatusBar(segments []statusSegment, width int) string {
+	available := width - 4 // StatusBarStyle padding and a safety margin
+
+	byPriority := append([]statusSegment{}, segments...)
+	sort.SliceStable(byPriority, func(i, j int) bool { return byPriority[i].priority > byPriority[j].priority })
+	dropped := map[int]bool{}
+	for _, candidate := range byPriority {
+		if statusWidth(segments, dropped) <= available || candidate.priority == 0 {
+			break
+		}
+		dropped[candidate.priority] = true
+	}
+
+	// Only the branch is left when the bar still overflows
+	overflow := statusWidth(segments, dropped) - available
+	var parts []string
+	for _, segment := range segments {
+		if dropped[segment.priority] {
+			continue
+		}
+		text := segment.text
+		if overflow > 0 && segment.priority == 0 {
+			text = truncateText(text, max(lipgloss.Width(text)-overflow, 1))
+		}
+		parts = append(parts, segment.style.Render(text))
+	}
+	return StatusBarStyle.Render(strings.Join(parts, BlurredStyle.Render(statusSeparator)))
+}
+
+func statusWidth(segments []statusSegment, dropped map[int]bool) int {
+	width := 0
+	for _, segment := range segments {
+		if dropped[segment.priority] {
+			continue
+		}
+		if width > 0 {
+			width += lipgloss.Width(statusSeparator)
+		}
+		width += lipgloss.Width(segment.text)
+	}
+	return width
+}
+
+func truncateText(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
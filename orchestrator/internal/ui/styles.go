@@ -24,6 +24,57 @@ var (
 			Foreground(lipgloss.Color("#CBC8C6")).
 			MarginTop(1).
 			Padding(0, 2)
+	ConfirmStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#4E5EDE")).
+			MarginTop(1).
+			Padding(0, 2)
+	CardStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#4E5EDE")).
+			Padding(0, 1).
+			MarginLeft(2)
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#E5A50A")).
+			Padding(0, 2)
+	StatusBarStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Padding(0, 2)
+	StatusBarBranchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#4E5EDE"))
+	StatusBarDirtyStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#E5A50A"))
+	StagedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#26A269"))
+	UnstagedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#E01B24"))
+	DiffAddedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#26A269"))
+	DiffRemovedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#E01B24"))
+	DiffHunkStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#2A7BDE"))
+	DiffFileStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#4E5EDE")).
+			Bold(true)
+	DiffAddedLineStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#12261E"))
+	DiffRemovedLineStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#2D1215"))
+	DiffAddedWordStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#1E5631"))
+	DiffRemovedWordStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#6B1F25"))
+	SyntaxKeywordStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#C061CB"))
+	SyntaxStringStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#E9C46A"))
+	SyntaxCommentStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("240")).
+				Italic(true)
+	SyntaxNumberStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#62A0EA"))
+	CommitHashStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#E5A50A"))
 	MessageStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#CBC8C6"))
 	ZshModeInputBoxStyle = lipgloss.NewStyle().
@@ -36,6 +87,25 @@ var (
 				Padding(0, 2)
 )
 
+// commitTypeColors colour the Conventional Commits badges in /log
+var commitTypeColors = map[string]lipgloss.Color{
+	"feat":     lipgloss.Color("#26A269"),
+	"fix":      lipgloss.Color("#E01B24"),
+	"docs":     lipgloss.Color("#2A7BDE"),
+	"refactor": lipgloss.Color("#C061CB"),
+	"perf":     lipgloss.Color("#E5A50A"),
+	"test":     lipgloss.Color("#33C7DE"),
+}
+
+// CommitTypeStyle styles the badge of a Conventional Commits type
+func CommitTypeStyle(kind string) lipgloss.Style {
+	color, ok := commitTypeColors[kind]
+	if !ok {
+		color = lipgloss.Color("240")
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true)
+}
+
 func InitSpinnerStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 }
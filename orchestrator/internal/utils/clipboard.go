@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/atotto/clipboard"
+)
+
+// CopyToClipboard puts text on the system clipboard. It fails without a
+// clipboard tool (xclip, xsel, wl-copy), as over SSH, where
+// TerminalCopySequence is the fallback.
+func CopyToClipboard(text string) error {
+	if clipboard.Unsupported {
+		return fmt.Errorf("no clipboard tool found")
+	}
+	return clipboard.WriteAll(text)
+}
+
+// TerminalCopySequence returns the OSC 52 escape sequence asking the
+// terminal to copy text; not every terminal honours it. It must be printed
+// through the Bubble Tea program (tea.Printf), which owns stdout.
+func TerminalCopySequence(text string) string {
+	sequence := fmt.Sprintf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	if os.Getenv("TMUX") != "" {
+		// tmux only forwards escape sequences wrapped in its passthrough
+		sequence = "\x1bPtmux;\x1b" + sequence + "\x1b\\"
+	}
+	return sequence
+}
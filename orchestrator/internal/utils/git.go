@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"gemini-orchestrator/internal/models"
+)
+
+// HeadCommit returns the commit HEAD points to, or nil outside a repository
+func HeadCommit() *models.CommitInfo {
+	commit, _ := headCommit()
+	return commit
+}
+
+// NewCommitSince reports the commit HEAD gained since before was recorded at start
+// Moving HEAD to an existing commit (switching branches, pulling) is not a new commit
+func NewCommitSince(before *models.CommitInfo, start time.Time) *models.CommitInfo {
+	after, committed := headCommit()
+	if after == nil || (before != nil && after.SHA == before.SHA) {
+		return nil
+	}
+	// Commit timestamps have second precision
+	if committed.Before(start.Truncate(time.Second)) {
+		return nil
+	}
+	if before != nil {
+		if err := exec.Command("git", "merge-base", "--is-ancestor", before.SHA, after.SHA).Run(); err != nil {
+			return nil
+		}
+	}
+	return after
+}
+
+func headCommit() (*models.CommitInfo, time.Time) {
+	output, err := exec.Command("git", "log", "-1", "--format=%H%x00%ct%x00%s").Output()
+	if err != nil {
+		return nil, time.Time{}
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(string(output)), "\x00", 3)
+	if len(fields) != 3 || fields[0] == "" {
+		return nil, time.Time{}
+	}
+
+	seconds, _ := strconv.ParseInt(fields[1], 10, 64)
+	return &models.CommitInfo{SHA: fields[0], Subject: fields[2]}, time.Unix(seconds, 0)
+}
@@ -0,0 +1,15 @@
+package utils
+
+import "unicode/utf8"
+
+// TruncateBytes cuts s to at most limit bytes without splitting a
+// multi-byte rune, so text sent to a model stays valid UTF-8
+func TruncateBytes(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateBytes(t *testing.T) {
+	tests := []struct {
+		text     string
+		limit    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exact", 5, "exact"},
+		{"ascii text", 5, "ascii"},
+		{"café", 4, "caf"}, // é is two bytes
+		{"café", 5, "café"},
+		{"日本語", 4, "日"},
+		{"日本語", 2, ""},
+		{"👍ok", 3, ""},
+	}
+
+	for _, test := range tests {
+		actual := TruncateBytes(test.text, test.limit)
+		if actual != test.expected {
+			t.Errorf("TruncateBytes(%q, %d): expected %q, got %q", test.text, test.limit, test.expected, actual)
+		}
+		if !utf8.ValidString(actual) {
+			t.Errorf("TruncateBytes(%q, %d) returned invalid UTF-8", test.text, test.limit)
+		}
+	}
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
+	"strconv"
 	"strings"
 
 	"gemini-orchestrator/internal/commands"
+	"gemini-orchestrator/internal/config"
+	"gemini-orchestrator/internal/control"
+	"gemini-orchestrator/internal/git"
+	"gemini-orchestrator/internal/github"
+	"gemini-orchestrator/internal/llm"
 	"gemini-orchestrator/internal/models"
 	"gemini-orchestrator/internal/ui"
 
@@ -15,10 +23,29 @@ import (
 
 type orchestratorModel struct {
 	models.Model
+	startupScript []string // Arguments of "gemini-orchestrator run", run once the UI starts
 }
 
 func (m orchestratorModel) Init() tea.Cmd {
-	return models.ListenForSignals()
+	cmds := []tea.Cmd{
+		models.ListenForSignals(),
+		commands.StartupDoctor(),
+		config.Watch(m.Config.Paths, config.Stat(m.Config.Paths)),
+		git.RefreshStatus(),
+	}
+	if m.Control != nil {
+		cmds = append(cmds, m.Control.Listen())
+	}
+	if m.Watcher != nil {
+		cmds = append(cmds, m.Watcher.Watch())
+	}
+	if len(m.startupScript) > 0 {
+		args := m.startupScript
+		cmds = append(cmds, func() tea.Msg {
+			return models.RunScriptMsg{Args: args}
+		})
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m orchestratorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -33,20 +60,96 @@ func (m orchestratorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case models.BuildCompleteMsg:
 		m.IsBuilding = false
 		m.Messages = append(m.Messages, "✅ Build successful! Relaunch app to get new update?")
-		return m, nil
+		return m, commands.AdvanceScript(&m.Model, nil)
 	case models.BuildErrorMsg:
 		m.IsBuilding = false
 		m.Messages = append(m.Messages, fmt.Sprintf("❌ Build failed: %v", msg.Err))
+		return m, commands.AdvanceScript(&m.Model, msg.Err)
+	case models.ExecFinishedMsg:
+		finished := map[string]string{"command": msg.Command, "exit_code": "0"}
+		if msg.Err != nil {
+			m.AddResult(fmt.Sprintf("❌ %v", msg.Err))
+			finished["exit_code"] = "1"
+			finished["error"] = msg.Err.Error()
+			var exitErr *exec.ExitError
+			if errors.As(msg.Err, &exitErr) {
+				finished["exit_code"] = strconv.Itoa(exitErr.ExitCode())
+			}
+		}
+		m.Publish(control.EventScriptFinished, finished)
+		if msg.NewCommit != nil {
+			m.AddResult(fmt.Sprintf("Committed %.7s %s", msg.NewCommit.SHA, msg.NewCommit.Subject))
+			m.Publish(control.EventCommitCreated, map[string]string{"sha": msg.NewCommit.SHA, "subject": msg.NewCommit.Subject})
+		}
+		// Scripts may have switched branches or created a pull request
+		return m, tea.Batch(commands.AdvanceScript(&m.Model, msg.Err), commands.RefreshStatusBar(&m.Model, true))
+	case control.RequestMsg:
+		cmd := commands.HandleControlRequest(msg, &m.Model)
+		return m, tea.Batch(cmd, m.Control.Listen(), commands.RefreshStatusBar(&m.Model, false))
+	case models.CommandFailedMsg:
+		return m, commands.AdvanceScript(&m.Model, msg.Err)
+	case models.PluginOutputMsg:
+		for _, line := range strings.Split(msg.Output, "\n") {
+			if line != "" {
+				m.AddResult(line)
+			}
+		}
+		if msg.Err != nil {
+			m.AddResult(fmt.Sprintf("❌ /%s: %v", msg.Name, msg.Err))
+		}
+		return m, tea.Batch(commands.AdvanceScript(&m.Model, msg.Err), commands.RefreshStatusBar(&m.Model, false))
+	case models.DoctorMsg:
+		commands.ReportDoctor(msg, &m.Model)
+		if msg.Startup {
+			return m, nil
+		}
+		return m, commands.AdvanceScript(&m.Model, nil)
+	case models.StartIssueMsg:
+		return m, commands.ContinueStart(msg, &m.Model)
+	case models.IssueAssignedMsg:
+		return m, commands.ReportIssueAssigned(msg, &m.Model)
+	case models.FinishMsg:
+		return m, commands.ReportFinish(msg, &m.Model)
+	case llm.ChunkMsg:
+		if msg.Stream == m.ChatStream {
+			return m, commands.ContinueChat(msg, &m.Model)
+		}
+	case llm.DoneMsg:
+		if msg.Stream == m.ChatStream {
+			return m, commands.FinishChat(msg, &m.Model)
+		}
+	case models.ModelListMsg:
+		return m, commands.ReportModels(msg, &m.Model)
+	case config.ChangedMsg:
+		commands.ReloadConfig(&m.Model)
+		return m, config.Watch(m.Config.Paths, msg.Fingerprint)
+	case git.ChangedMsg:
+		// Files edited in another window or branches switched elsewhere
+		return m, tea.Batch(commands.RefreshStatusBar(&m.Model, false), m.Watcher.Watch())
+	case git.StatusMsg:
+		return m, commands.UpdateGitStatus(msg, &m.Model)
+	case github.PullRequestMsg:
+		commands.UpdatePullRequest(msg, &m.Model)
 		return m, nil
+	case models.RunScriptMsg:
+		return m, commands.HandleRun(msg.Args, &m.Model)
 	case models.ShutdownMsg:
 		return m, tea.Quit
 	case models.CtrlCTimeoutMsg:
 		m.ShowExitConfirm = false
 		return m, nil
 	case tea.KeyMsg:
+		if m.Panel != nil && msg.Type != tea.KeyCtrlC {
+			return m.updatePanel(msg)
+		}
 		return m.handleKeyMsg(msg)
 	}
 
+	// Panels receive the results of their own background work
+	if m.Panel != nil {
+		return m.updatePanel(msg)
+	}
+
 	// Update spinner if building
 	if m.IsBuilding {
 		var spinnerCmd tea.Cmd
@@ -60,7 +163,31 @@ func (m orchestratorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmd, textInputCmd)
 }
 
+func (m orchestratorModel) updatePanel(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.ShowExitConfirm {
+		m.ShowExitConfirm = false
+	}
+
+	cmd := m.Panel.Update(msg, &m.Model)
+	// A batch script waits for a panel opened by one of its steps to close
+	if m.Panel == nil {
+		return m, tea.Batch(cmd, commands.AdvanceScript(&m.Model, nil), commands.RefreshStatusBar(&m.Model, false))
+	}
+	return m, cmd
+}
+
 func (m orchestratorModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// A pending yes/no question captures all keys except Ctrl+C
+	if m.Confirm != nil && msg.Type != tea.KeyCtrlC {
+		return m.handleConfirmKey(msg)
+	}
+
+	// Esc and Ctrl+C stop a streaming chat answer before anything else
+	if m.ChatStream != nil && (msg.Type == tea.KeyEsc || msg.Type == tea.KeyCtrlC) {
+		commands.CancelChat(&m.Model)
+		return m, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyCtrlC:
 		if m.ShowExitConfirm {
@@ -252,6 +379,25 @@ func (m orchestratorModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m orchestratorModel) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var yes bool
+	switch {
+	case msg.Type == tea.KeyEnter, msg.String() == "y", msg.String() == "Y":
+		yes = true
+	case msg.Type == tea.KeyEsc, msg.String() == "n", msg.String() == "N":
+		yes = false
+	default:
+		return m, nil
+	}
+
+	if m.ShowExitConfirm {
+		m.ShowExitConfirm = false
+	}
+	confirm := m.Confirm
+	m.Confirm = nil
+	return m, confirm.OnAnswer(yes, &m.Model)
+}
+
 func (m orchestratorModel) handleEnterKey() (tea.Model, tea.Cmd) {
 	if m.ShowExitConfirm {
 		m.ShowExitConfirm = false
@@ -266,11 +412,15 @@ func (m orchestratorModel) handleEnterKey() (tea.Model, tea.Cmd) {
 		inputValue := strings.TrimSpace(m.TextInput.Value())
 
 		// Handle zsh mode commands
+		var cmd tea.Cmd
 		if m.ZshMode {
-			return m, commands.HandleZshCommand(inputValue, &m.Model)
+			cmd = commands.HandleZshCommand(inputValue, &m.Model)
+		} else {
+			cmd = commands.HandleCommand(inputValue, &m.Model)
 		}
 
-		return m, commands.HandleCommand(inputValue, &m.Model)
+		// Keep the status bar current after every command
+		return m, tea.Batch(cmd, commands.RefreshStatusBar(&m.Model, false))
 	}
 	return m, nil
 }
@@ -312,14 +462,60 @@ func (m orchestratorModel) View() string {
 	return ui.RenderView(m.Model)
 }
 
+func usage() {
+	fmt.Println("Usage: gemini-orchestrator [command]")
+	fmt.Println("")
+	fmt.Println("Commands:")
+	fmt.Println("  run <file> [NAME=value ...]    Start the orchestrator and run a batch script")
+	fmt.Println("  send [options] [command]       Send a command to a running orchestrator (see send -h)")
+	fmt.Println("  help                           Show this help message")
+}
+
 func main() {
+	var startupScript []string
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			if len(os.Args) < 3 {
+				usage()
+				os.Exit(1)
+			}
+			startupScript = os.Args[2:]
+		case "send":
+			os.Exit(runSend(os.Args[2:]))
+		case "help", "-h", "--help":
+			usage()
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", os.Args[1])
+			usage()
+			os.Exit(1)
+		}
+	}
+
 	ui.ClearConsole()
 
 	initialModel := models.InitialModel()
-	wrappedModel := orchestratorModel{Model: initialModel}
+	wrappedModel := orchestratorModel{Model: initialModel, startupScript: startupScript}
+
+	// Listen for editor and tool integrations on a per-session socket
+	server, err := control.Start(control.SessionPath())
+	if err != nil {
+		wrappedModel.Messages = append(wrappedModel.Messages, fmt.Sprintf("⚠️  Control socket unavailable: %v", err))
+	} else {
+		defer server.Close()
+		os.Setenv(control.SocketEnv, server.Path())
+		wrappedModel.Control = server
+	}
+
+	// Keep git-derived state fresh when the repository changes outside the orchestrator
+	if watcher, err := git.NewWatcher(); err == nil {
+		wrappedModel.Watcher = watcher
+	}
 
 	p := tea.NewProgram(wrappedModel)
 	if _, err := p.Run(); err != nil {
+		server.Close()
 		log.Fatal(err)
 		os.Exit(1)
 	}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gemini-orchestrator/internal/control"
+)
+
+// runSend implements "gemini-orchestrator send", a small client for the control socket
+func runSend(args []string) int {
+	flags := flag.NewFlagSet("send", flag.ContinueOnError)
+	socket := flags.String("socket", "", "socket path (default: $"+control.SocketEnv+" or the newest session)")
+	insert := flags.String("insert", "", "insert text into the input field")
+	open := flags.String("open", "", "add a file to the session's file context")
+	subscribe := flags.Bool("subscribe", false, "print events as JSON lines; arguments filter event names")
+	raw := flags.String("json", "", "send a raw JSON request")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gemini-orchestrator send [options] [command]")
+		fmt.Fprintln(flags.Output(), "")
+		fmt.Fprintln(flags.Output(), "Examples:")
+		fmt.Fprintln(flags.Output(), "  gemini-orchestrator send /commit fix login bug")
+		fmt.Fprintln(flags.Output(), "  gemini-orchestrator send --open internal/ui/render.go")
+		fmt.Fprintln(flags.Output(), "  gemini-orchestrator send --subscribe commit.created script.finished")
+		fmt.Fprintln(flags.Output(), "")
+		fmt.Fprintln(flags.Output(), "Options:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var req control.Request
+	switch {
+	case *raw != "":
+		if err := json.Unmarshal([]byte(*raw), &req); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid JSON request: %v\n", err)
+			return 2
+		}
+	case *subscribe:
+		req = control.Request{Type: control.RequestSubscribe, Events: flags.Args()}
+	case *insert != "":
+		req = control.Request{Type: control.RequestInsert, Text: *insert}
+	case *open != "":
+		// The session resolves paths against its own directory, which
+		// is not ours when sending from a subdirectory
+		path, err := filepath.Abs(*open)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid path %s: %v\n", *open, err)
+			return 2
+		}
+		req = control.Request{Type: control.RequestOpen, Path: path}
+	case flags.NArg() > 0:
+		req = control.Request{Type: control.RequestCommand, Command: strings.Join(flags.Args(), " ")}
+	default:
+		req = control.Request{Type: control.RequestPing}
+	}
+
+	path := *socket
+	if path == "" {
+		found, err := control.FindSession()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		path = found
+	}
+
+	client, err := control.Dial(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to %s: %v\n", path, err)
+		return 1
+	}
+	defer client.Close()
+
+	resp, err := client.Send(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send request: %v\n", err)
+		return 1
+	}
+	if !resp.OK {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		return 1
+	}
+	if resp.Queued {
+		fmt.Fprintln(os.Stderr, "Orchestrator is busy; the request will run when it resumes")
+	}
+
+	switch req.Type {
+	case control.RequestPing:
+		fmt.Printf("Session %s (pid %s) in %s\n", path, resp.Data["pid"], resp.Data["cwd"])
+	case control.RequestSubscribe:
+		if err := client.Stream(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Connection closed: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
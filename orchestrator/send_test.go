@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gemini-orchestrator/internal/control"
+)
+
+// TestSendOpenFromSubdirectory sends --open from below the session's
+// directory, as FindSession allows, and checks the session gets a path it
+// can resolve
+func TestSendOpenFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	server, err := control.Start(filepath.Join(t.TempDir(), "session.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	subdirectory := filepath.Join(root, "pkg")
+	if err := os.Mkdir(subdirectory, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(subdirectory)
+
+	requests := make(chan control.Request, 1)
+	go func() {
+		msg := server.Listen()().(control.RequestMsg)
+		requests <- msg.Request
+		msg.Reply(map[string]string{"path": msg.Request.Path}, nil)
+	}()
+
+	if code := runSend([]string{"--socket", server.Path(), "--open", "foo.go"}); code != 0 {
+		t.Fatalf("send --open exited with %d", code)
+	}
+	req := <-requests
+	if req.Type != control.RequestOpen {
+		t.Errorf("expected an open request, got %q", req.Type)
+	}
+	if expected := filepath.Join(subdirectory, "foo.go"); req.Path != expected {
+		t.Errorf("expected path %q, got %q", expected, req.Path)
+	}
+}